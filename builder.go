@@ -0,0 +1,205 @@
+package cuessz
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Uint8 returns a uint8 def
+func Uint8() Def { return Def{Type: TypeUint8} }
+
+// Uint16 returns a uint16 def
+func Uint16() Def { return Def{Type: TypeUint16} }
+
+// Uint32 returns a uint32 def
+func Uint32() Def { return Def{Type: TypeUint32} }
+
+// Uint64 returns a uint64 def
+func Uint64() Def { return Def{Type: TypeUint64} }
+
+// Uint128 returns a uint128 def
+func Uint128() Def { return Def{Type: TypeUint128} }
+
+// Uint256 returns a uint256 def
+func Uint256() Def { return Def{Type: TypeUint256} }
+
+// Boolean returns a boolean def
+func Boolean() Def { return Def{Type: TypeBoolean} }
+
+// Ref returns a def referencing the top-level def called name
+func Ref(name string) Def { return Def{Type: TypeRef, Ref: name} }
+
+// Vector returns a vector of size elements of elem
+func Vector(elem Def, size uint64) Def {
+	return Def{Type: TypeVector, Size: size, Children: []Field{{Name: "element", Def: elem}}}
+}
+
+// List returns a list of at most limit elements of elem
+func List(elem Def, limit uint64) Def {
+	return Def{Type: TypeList, Limit: limit, Children: []Field{{Name: "element", Def: elem}}}
+}
+
+// BitVector returns a bitvector of size bits
+func BitVector(size uint64) Def { return Def{Type: TypeBitVector, Size: size} }
+
+// BitList returns a bitlist of at most limit bits
+func BitList(limit uint64) Def { return Def{Type: TypeBitList, Limit: limit} }
+
+// ByteVector returns a vector of size bytes, in the canonical vector-of-uint8 form
+func ByteVector(size uint64) Def { return Vector(Uint8(), size) }
+
+// ByteList returns a list of at most limit bytes, in the canonical list-of-uint8 form
+func ByteList(limit uint64) Def { return List(Uint8(), limit) }
+
+// WithDescription returns a copy of the def with its description set
+func (d Def) WithDescription(description string) Def {
+	d.Description = &description
+	return d
+}
+
+// WithSizeExpr returns a copy of the def sized by a constant expression,
+// e.g. "SLOTS_PER_HISTORICAL_ROOT"; SchemaBuilder.Build resolves it
+func (d Def) WithSizeExpr(expr string) Def {
+	d.SizeExpr = expr
+	return d
+}
+
+// WithLimitExpr returns a copy of the def limited by a constant expression,
+// e.g. "MAX_VALIDATORS_PER_COMMITTEE"; SchemaBuilder.Build resolves it
+func (d Def) WithLimitExpr(expr string) Def {
+	d.LimitExpr = expr
+	return d
+}
+
+// ContainerBuilder builds a named container, progressive container or union field by field:
+//
+//	cuessz.Container("Checkpoint").
+//		Field("epoch", cuessz.Uint64()).
+//		Field("root", cuessz.Ref("Root"))
+type ContainerBuilder struct {
+	name string
+	def  Def
+}
+
+// Container starts a container def called name
+func Container(name string) *ContainerBuilder {
+	return &ContainerBuilder{name: name, def: Def{Type: TypeContainer}}
+}
+
+// ProgressiveContainer starts a progressive container def called name
+// with the given active_fields bits
+func ProgressiveContainer(name string, activeFields ...int) *ContainerBuilder {
+	return &ContainerBuilder{name: name, def: Def{
+		Type:         TypeProgressiveContainer,
+		ActiveFields: slices.Clone(activeFields),
+	}}
+}
+
+// Union starts a union def called name; each Field is one of its options
+func Union(name string) *ContainerBuilder {
+	return &ContainerBuilder{name: name, def: Def{Type: TypeUnion}}
+}
+
+// Field appends a field
+func (b *ContainerBuilder) Field(name string, def Def) *ContainerBuilder {
+	b.def.Children = append(b.def.Children, Field{Name: name, Def: def})
+	return b
+}
+
+// Describe sets the container's description
+func (b *ContainerBuilder) Describe(description string) *ContainerBuilder {
+	b.def.Description = &description
+	return b
+}
+
+// Name returns the name the container is registered under in a schema
+func (b *ContainerBuilder) Name() string {
+	return b.name
+}
+
+// Def returns a copy of the def built so far, so it can also be nested inline
+func (b *ContainerBuilder) Def() Def {
+	return b.def.Clone()
+}
+
+// SchemaBuilder collects named defs into a Schema
+type SchemaBuilder struct {
+	schema *Schema
+	err    error
+}
+
+// NewSchema starts an empty schema with the default version
+func NewSchema() *SchemaBuilder {
+	return &SchemaBuilder{schema: &Schema{Version: defaultSchemaVersion, Defs: make(map[string]Def)}}
+}
+
+// Version sets the schema version
+func (b *SchemaBuilder) Version(version string) *SchemaBuilder {
+	b.schema.Version = version
+	return b
+}
+
+// Constant declares a constant that size and limit expressions may use
+func (b *SchemaBuilder) Constant(name string, value uint64) *SchemaBuilder {
+	if b.schema.Constants == nil {
+		b.schema.Constants = make(map[string]uint64)
+	}
+	b.schema.Constants[name] = value
+	return b
+}
+
+// Def adds a top-level def called name
+// Adding the same name twice is reported by Build
+func (b *SchemaBuilder) Def(name string, def Def) *SchemaBuilder {
+	if _, ok := b.schema.Defs[name]; ok && b.err == nil {
+		b.err = fmt.Errorf("def '%s' is defined more than once", name)
+	}
+	b.schema.Defs[name] = def.Clone()
+	return b
+}
+
+// Add adds containers under their own names
+func (b *SchemaBuilder) Add(containers ...*ContainerBuilder) *SchemaBuilder {
+	for _, c := range containers {
+		b.Def(c.name, c.def)
+	}
+	return b
+}
+
+// Roots marks defs as entry points, see Schema.Roots
+func (b *SchemaBuilder) Roots(names ...string) *SchemaBuilder {
+	b.schema.Roots = append(b.schema.Roots, names...)
+	return b
+}
+
+// Build resolves constant expressions and validates the schema
+// The builder must not be used afterwards
+func (b *SchemaBuilder) Build() (*Schema, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	s := b.schema
+	if err := s.ResolveConstants(); err != nil {
+		return nil, err
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	for _, root := range s.Roots {
+		if _, ok := s.Defs[root]; !ok {
+			return nil, fmt.Errorf("root '%s' is not defined in schema defs (defined: %v)", root, slices.Sorted(maps.Keys(s.Defs)))
+		}
+	}
+	return s, nil
+}
+
+// MustBuild is like Build but panics on error; it is meant for tests and
+// package-level schema variables
+func (b *SchemaBuilder) MustBuild() *Schema {
+	s, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
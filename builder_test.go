@@ -0,0 +1,132 @@
+package cuessz
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaBuilder(t *testing.T) {
+	schema, err := NewSchema().
+		Constant("SLOTS_PER_HISTORICAL_ROOT", 8).
+		Def("Root", ByteVector(32)).
+		Add(
+			Container("Checkpoint").
+				Field("epoch", Uint64()).
+				Field("root", Ref("Root")),
+			Container("State").
+				Describe("A tiny beacon state").
+				Field("block_roots", Vector(Ref("Root"), 0).WithSizeExpr("SLOTS_PER_HISTORICAL_ROOT")).
+				Field("finalized_checkpoint", Ref("Checkpoint")).
+				Field("justified_checkpoint", Ref("Checkpoint")),
+		).
+		Def("Unrelated", Uint64()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if got := schema.Defs["State"].Children[0].Def.Size; got != 8 {
+		t.Errorf("expected block_roots size resolved to 8, got %d", got)
+	}
+
+	// The builder output must match the same schema written as JSON
+	parsed, err := ParseJSON(graphSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	got, err := schema.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	want, err := parsed.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("expected built schema to match graphSchema:\n%s", mustCanonical(t, schema))
+	}
+}
+
+func TestSchemaBuilder_Errors(t *testing.T) {
+	_, err := NewSchema().
+		Add(Container("Checkpoint").Field("root", Ref("Root"))).
+		Build()
+	if err == nil || !strings.Contains(err.Error(), "'Root'") {
+		t.Errorf("expected missing ref error, got: %v", err)
+	}
+
+	_, err = NewSchema().
+		Def("Bits", List(Boolean(), 0)).
+		Build()
+	if !errors.Is(err, ErrInvalidDef) {
+		t.Errorf("expected ErrInvalidDef for list without limit, got: %v", err)
+	}
+
+	_, err = NewSchema().
+		Def("A", Uint8()).
+		Def("A", Uint16()).
+		Build()
+	if err == nil || !strings.Contains(err.Error(), "more than once") {
+		t.Errorf("expected duplicate def error, got: %v", err)
+	}
+
+	_, err = NewSchema().
+		Def("Roots", Vector(Uint8(), 0).WithSizeExpr("UNDECLARED")).
+		Build()
+	if !errors.Is(err, ErrUnknownConstant) {
+		t.Errorf("expected ErrUnknownConstant, got: %v", err)
+	}
+
+	_, err = NewSchema().Def("A", Uint8()).Roots("B").Build()
+	if err == nil || !strings.Contains(err.Error(), "root 'B'") {
+		t.Errorf("expected unknown root error, got: %v", err)
+	}
+}
+
+func TestContainerBuilder_Def(t *testing.T) {
+	b := ProgressiveContainer("Message", 1, 0, 1).
+		Field("text", ByteList(256).WithDescription("utf-8 text")).
+		Field("timestamp", Uint64())
+
+	want := Def{
+		Type:         TypeProgressiveContainer,
+		ActiveFields: []int{1, 0, 1},
+		Children: []Field{
+			{Name: "text", Def: Def{Type: TypeList, Limit: 256, Description: ptr("utf-8 text"), Children: []Field{
+				{Name: "element", Def: Def{Type: TypeUint8}},
+			}}},
+			{Name: "timestamp", Def: Def{Type: TypeUint64}},
+		},
+	}
+	if got := b.Def(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected def:\n got  %+v\n want %+v", got, want)
+	}
+
+	schema := NewSchema().Add(b).MustBuild()
+	if _, ok := schema.Defs["Message"]; !ok {
+		t.Errorf("expected Message def in schema")
+	}
+
+	// Later fields must not leak into a def that was already taken
+	before := b.Def()
+	b.Field("extra", Boolean())
+	if len(before.Children) != 2 {
+		t.Errorf("expected earlier Def to keep 2 children, got %d", len(before.Children))
+	}
+	if len(schema.Defs["Message"].Children) != 2 {
+		t.Errorf("expected schema def to keep 2 children, got %d", len(schema.Defs["Message"].Children))
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+func mustCanonical(t *testing.T, s *Schema) string {
+	t.Helper()
+	out, err := s.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON failed: %v", err)
+	}
+	return string(out)
+}
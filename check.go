@@ -0,0 +1,160 @@
+package cuessz
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// maxActiveFields is the maximum length of a progressive container's active_fields
+const maxActiveFields = 256
+
+// checkDefs checks the structure of every def the way ssz_schema.cue does, plus the
+// active_fields rules documented there, so schemas built in Go get the same checks
+func (s *Schema) checkDefs() error {
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		if err := checkDef(&def, ""); err != nil {
+			return fmt.Errorf("def '%s' %w", name, err)
+		}
+	}
+	return nil
+}
+
+// checkDef checks a single def and its children, pathPrefix locating it for errors
+func checkDef(d *Def, pathPrefix string) error {
+	location := "is invalid"
+	if pathPrefix != "" {
+		location = "is invalid at " + pathPrefix
+	}
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("%s: %w: %s", location, ErrInvalidDef, fmt.Sprintf(format, args...))
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64, TypeUint128, TypeUint256, TypeBoolean:
+	case TypeVector, TypeBitVector, TypeByteVector:
+		if d.Size == 0 && d.SizeExpr == "" {
+			return fail("%s requires a size", d.Type)
+		}
+		if d.Size > maxSSZSize {
+			return fail("size %d exceeds %d", d.Size, uint64(maxSSZSize))
+		}
+	case TypeList, TypeBitList, TypeByteList:
+		if d.Limit == 0 && d.LimitExpr == "" {
+			return fail("%s requires a limit", d.Type)
+		}
+	case TypeContainer, TypeProgressiveContainer, TypeUnion:
+	case TypeRef:
+		if d.Ref == "" {
+			return fail("def has type 'ref' but no ref specified")
+		}
+	default:
+		return fail("invalid type '%s'", d.Type)
+	}
+
+	// Like the closed CUE #Def, only the fields of the def's type may be set
+	switch d.Type {
+	case TypeVector, TypeBitVector, TypeByteVector:
+	default:
+		if d.Size != 0 || d.SizeExpr != "" {
+			return fail("size is only allowed on vector, bitvector and bytevector, not %s", d.Type)
+		}
+	}
+	switch d.Type {
+	case TypeList, TypeBitList, TypeByteList:
+	default:
+		if d.Limit != 0 || d.LimitExpr != "" {
+			return fail("limit is only allowed on list, bitlist and bytelist, not %s", d.Type)
+		}
+	}
+	if d.Type != TypeRef && d.Ref != "" {
+		return fail("ref is only allowed on ref, not %s", d.Type)
+	}
+
+	switch d.Type {
+	case TypeVector, TypeList:
+		if len(d.Children) != 1 {
+			return fail("%s must have exactly one child, got %d", d.Type, len(d.Children))
+		}
+	case TypeContainer, TypeProgressiveContainer, TypeUnion:
+		seen := make(map[string]bool, len(d.Children))
+		for _, child := range d.Children {
+			if d.Type != TypeUnion && seen[child.Name] {
+				return fail("duplicate field name '%s'", child.Name)
+			}
+			seen[child.Name] = true
+		}
+	default:
+		if len(d.Children) != 0 {
+			return fail("%s cannot have children", d.Type)
+		}
+	}
+
+	if d.Type == TypeProgressiveContainer {
+		if err := checkActiveFields(d); err != nil {
+			return fail("%v", err)
+		}
+	} else if len(d.ActiveFields) != 0 {
+		return fail("active_fields is only allowed on progressive_container")
+	}
+
+	for i := range d.Children {
+		childPath := fmt.Sprintf("field '%s'", d.Children[i].Name)
+		if pathPrefix != "" {
+			childPath = pathPrefix + " -> " + childPath
+		}
+		if err := checkDef(&d.Children[i].Def, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkActiveFields enforces the active_fields rules from ssz_schema.cue: at most 256
+// entries of 0 or 1, one 1 per child, and no trailing 0
+func checkActiveFields(d *Def) error {
+	if len(d.ActiveFields) > maxActiveFields {
+		return fmt.Errorf("active_fields has %d entries, at most %d allowed", len(d.ActiveFields), maxActiveFields)
+	}
+
+	active := 0
+	for _, bit := range d.ActiveFields {
+		switch bit {
+		case 0:
+		case 1:
+			active++
+		default:
+			return fmt.Errorf("active_fields entries must be 0 or 1, got %d", bit)
+		}
+	}
+	if active != len(d.Children) {
+		return fmt.Errorf("active_fields has %d active entries but there are %d children", active, len(d.Children))
+	}
+	if n := len(d.ActiveFields); n > 0 && d.ActiveFields[n-1] != 1 {
+		return fmt.Errorf("active_fields cannot end in 0")
+	}
+	return nil
+}
+
+// checkRefs validates that all type references point to valid top-level defs,
+// like checkRefsWithCUE does for CUE values
+func (s *Schema) checkRefs() error {
+	for _, defName := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[defName]
+		for _, refLoc := range collectDefRefs(&def) {
+			if _, ok := s.Defs[refLoc.ref]; !ok {
+				return fmt.Errorf("def '%s' has invalid reference to '%s' (at %s) - referenced type is not defined in schema defs",
+					defName, refLoc.ref, refLoc.path)
+			}
+		}
+	}
+	return nil
+}
+
+// checkCycles detects cycles in type references, like checkCyclesWithCUE does for
+// CUE values; the topological sort already has to find them
+func (s *Schema) checkCycles() error {
+	_, err := s.TopoSort()
+	return err
+}
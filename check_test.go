@@ -0,0 +1,134 @@
+package cuessz
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate_GoSchemaRefs(t *testing.T) {
+	schema := &Schema{
+		Defs: map[string]Def{
+			"TypeA": {
+				Type: TypeContainer,
+				Children: []Field{
+					{Name: "my_field", Def: Def{Type: TypeRef, Ref: "NonExistent"}},
+				},
+			},
+		},
+	}
+
+	err := schema.Validate()
+	if err == nil {
+		t.Fatal("expected error for invalid ref, got nil")
+	}
+	for _, want := range []string{"TypeA", "NonExistent", "my_field"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error should mention %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestValidate_GoSchemaCycles(t *testing.T) {
+	schema := &Schema{
+		Defs: map[string]Def{
+			"Node": {
+				Type: TypeContainer,
+				Children: []Field{
+					{Name: "children", Def: Def{Type: TypeList, Limit: 100, Children: []Field{
+						{Name: "element", Def: Def{Type: TypeRef, Ref: "Node"}},
+					}}},
+				},
+			},
+		},
+	}
+
+	if err := schema.Validate(); !errors.Is(err, ErrRecursiveType) {
+		t.Errorf("expected ErrRecursiveType for nested recursion, got: %v", err)
+	}
+}
+
+func TestValidate_AfterMutation(t *testing.T) {
+	schema, err := ParseJSON(graphSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	if err := schema.Validate(); err != nil {
+		t.Fatalf("expected parsed schema to validate, got: %v", err)
+	}
+
+	// Introduce a cycle after parsing: Root now points back at Checkpoint
+	schema.Defs["Root"] = Def{Type: TypeContainer, Children: []Field{
+		{Name: "checkpoint", Def: Def{Type: TypeRef, Ref: "Checkpoint"}},
+	}}
+	if err := schema.Validate(); !errors.Is(err, ErrRecursiveType) {
+		t.Errorf("expected ErrRecursiveType after mutation, got: %v", err)
+	}
+}
+
+func TestValidate_GoSchemaStructure(t *testing.T) {
+	elem := []Field{{Name: "element", Def: Def{Type: TypeUint64}}}
+	tests := []struct {
+		name string
+		def  Def
+		want string
+	}{
+		{"invalid type", Def{Type: "uint512"}, "invalid type 'uint512'"},
+		{"vector without size", Def{Type: TypeVector, Children: elem}, "requires a size"},
+		{"list without limit", Def{Type: TypeList, Children: elem}, "requires a limit"},
+		{"oversized bitvector", Def{Type: TypeBitVector, Size: maxSSZSize + 1}, "exceeds"},
+		{"vector without child", Def{Type: TypeVector, Size: 4}, "exactly one child"},
+		{"ref without name", Def{Type: TypeRef}, "no ref specified"},
+		{"uint with children", Def{Type: TypeUint8, Children: elem}, "cannot have children"},
+		{"duplicate field", Def{Type: TypeContainer, Children: []Field{
+			{Name: "a", Def: Def{Type: TypeUint8}},
+			{Name: "a", Def: Def{Type: TypeUint8}},
+		}}, "duplicate field name 'a'"},
+		{"nested error", Def{Type: TypeContainer, Children: []Field{
+			{Name: "roots", Def: Def{Type: TypeVector, Size: 0, Children: elem}},
+		}}, "at field 'roots'"},
+		{"active fields count", Def{Type: TypeProgressiveContainer, ActiveFields: []int{1, 0, 1}, Children: []Field{
+			{Name: "a", Def: Def{Type: TypeUint8}},
+		}}, "2 active entries but there are 1 children"},
+		{"active fields trailing zero", Def{Type: TypeProgressiveContainer, ActiveFields: []int{1, 0}, Children: []Field{
+			{Name: "a", Def: Def{Type: TypeUint8}},
+		}}, "cannot end in 0"},
+		{"active fields on container", Def{Type: TypeContainer, ActiveFields: []int{1}}, "only allowed on progressive_container"},
+		{"active fields on uint", Def{Type: TypeUint8, ActiveFields: []int{1}}, "only allowed on progressive_container"},
+		{"size on uint", Def{Type: TypeUint64, Size: 8}, "size is only allowed on vector, bitvector and bytevector, not uint64"},
+		{"size on list", Def{Type: TypeList, Size: 4, Limit: 4, Children: elem}, "size is only allowed"},
+		{"size expression on container", Def{Type: TypeContainer, SizeExpr: "N"}, "size is only allowed"},
+		{"limit on uint", Def{Type: TypeUint32, Limit: 4}, "limit is only allowed on list, bitlist and bytelist, not uint32"},
+		{"limit on vector", Def{Type: TypeVector, Size: 4, Limit: 4, Children: elem}, "limit is only allowed"},
+		{"limit expression on container", Def{Type: TypeContainer, LimitExpr: "N"}, "limit is only allowed"},
+		{"ref on container", Def{Type: TypeContainer, Ref: "Other"}, "ref is only allowed on ref, not container"},
+		{"ref on uint", Def{Type: TypeUint8, Ref: "Other"}, "ref is only allowed"},
+		{"children on boolean", Def{Type: TypeBoolean, Children: elem}, "cannot have children"},
+		{"children on bitlist", Def{Type: TypeBitList, Limit: 8, Children: elem}, "cannot have children"},
+		{"children on ref", Def{Type: TypeRef, Ref: "Other", Children: elem}, "cannot have children"},
+	}
+
+	for _, tt := range tests {
+		schema := &Schema{Defs: map[string]Def{"Bad": tt.def}}
+		err := schema.Validate()
+		if !errors.Is(err, ErrInvalidDef) {
+			t.Errorf("%s: expected ErrInvalidDef, got: %v", tt.name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "Bad") {
+			t.Errorf("%s: expected error to mention %q and the def name, got: %v", tt.name, tt.want, err)
+		}
+	}
+
+	valid := &Schema{Defs: map[string]Def{
+		"Message": {Type: TypeProgressiveContainer, ActiveFields: []int{1, 0, 1}, Children: []Field{
+			{Name: "text", Def: Def{Type: TypeByteList, Limit: 256}},
+			{Name: "timestamp", Def: Def{Type: TypeUint64}},
+		}},
+		// Limits are not bounded by the 4-byte offsets, only sizes are
+		"Registry": {Type: TypeList, Limit: 1 << 40, Children: elem},
+	}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid schema, got: %v", err)
+	}
+}
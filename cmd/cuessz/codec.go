@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gfx-labs/cuessz"
+)
+
+func decodeCommand(args []string) int {
+	flags := flag.NewFlagSet("decode", flag.ExitOnError)
+	input := flags.String("input", "auto", "input encoding: auto, raw, hex, snappy (framed) or snappy-block")
+	output := flags.String("output", "json", "output format: json or yaml")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz decode [flags] <schema.json> <Def> [file]\n\n")
+		fmt.Fprintf(os.Stderr, "Reads SSZ bytes from file (or stdin) and prints the value of Def.\n")
+		fmt.Fprintf(os.Stderr, "With -input auto, snappy framed streams and 0x-prefixed hex are detected.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 || flags.NArg() > 3 {
+		flags.Usage()
+		return 1
+	}
+	schema, err := loadSchema(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	def := flags.Arg(1)
+
+	raw, err := readInput(flags.Arg(2))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	data, err := decodeInput(schema, def, raw, *input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	value, err := schema.UnmarshalSSZ(def, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	if *output != "json" && *output != "yaml" {
+		fmt.Fprintf(os.Stderr, "Error: unknown output format %q (must be json or yaml)\n", *output)
+		return 1
+	}
+	out, err := formatValue(schema, def, value, *output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	os.Stdout.Write(out)
+	return 0
+}
+
+func encodeCommand(args []string) int {
+	flags := flag.NewFlagSet("encode", flag.ExitOnError)
+	input := flags.String("input", "json", "input format: json or yaml")
+	output := flags.String("output", "hex", "output encoding: raw, hex, snappy (framed) or snappy-block")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz encode [flags] <schema.json> <Def> [file]\n\n")
+		fmt.Fprintf(os.Stderr, "Reads a value of Def from file (or stdin) and prints its SSZ bytes.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 || flags.NArg() > 3 {
+		flags.Usage()
+		return 1
+	}
+	schema, err := loadSchema(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	def := flags.Arg(1)
+
+	raw, err := readInput(flags.Arg(2))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	var value cuessz.Value
+	switch *input {
+	case "json":
+		value, err = schema.UnmarshalValueJSON(def, raw)
+	case "yaml":
+		value, err = schema.UnmarshalValueYAML(def, raw)
+	default:
+		err = fmt.Errorf("unknown input format %q (must be json or yaml)", *input)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	data, err := schema.MarshalSSZ(def, value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	switch *output {
+	case "raw":
+		os.Stdout.Write(data)
+	case "hex":
+		fmt.Printf("0x%s\n", hex.EncodeToString(data))
+	case "snappy", "snappy-block":
+		compressed, err := cuessz.CompressSnappy(data, snappyFormat(*output))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		os.Stdout.Write(compressed)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown output encoding %q (must be raw, hex, snappy or snappy-block)\n", *output)
+		return 1
+	}
+	return 0
+}
+
+// readInput reads a file, or stdin when file is empty or "-"
+func readInput(file string) ([]byte, error) {
+	if file == "" || file == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("stdin: failed to read: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read file: %w", file, err)
+	}
+	return data, nil
+}
+
+// decodeInput turns input bytes in the given encoding into SSZ bytes of def,
+// bounding snappy input by the def's max size
+func decodeInput(schema *cuessz.Schema, def string, data []byte, encoding string) ([]byte, error) {
+	if encoding == "auto" {
+		encoding = detectEncoding(data)
+	}
+
+	switch encoding {
+	case "raw":
+		return data, nil
+	case "hex":
+		text := strings.TrimPrefix(string(bytes.TrimSpace(data)), "0x")
+		out, err := hex.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex input: %w", err)
+		}
+		return out, nil
+	case "snappy", "snappy-block":
+		return schema.DecompressSSZ(def, data, snappyFormat(encoding))
+	default:
+		return nil, fmt.Errorf("unknown input encoding %q (must be auto, raw, hex, snappy or snappy-block)", encoding)
+	}
+}
+
+// detectEncoding guesses the encoding of input for -input auto. Raw SSZ may
+// start with "0x" too, so only input that is 0x-prefixed hex text throughout,
+// bar surrounding whitespace, is taken as hex
+func detectEncoding(data []byte) string {
+	if cuessz.IsSnappyFramed(data) {
+		return "snappy"
+	}
+	text, ok := bytes.CutPrefix(bytes.TrimSpace(data), []byte("0x"))
+	if ok && len(text)%2 == 0 && !bytes.ContainsFunc(text, func(r rune) bool {
+		return !strings.ContainsRune("0123456789abcdefABCDEF", r)
+	}) {
+		return "hex"
+	}
+	return "raw"
+}
+
+// snappyFormat maps the snappy encoding names of the -input and -output flags
+func snappyFormat(encoding string) cuessz.SnappyFormat {
+	if encoding == "snappy-block" {
+		return cuessz.SnappyBlock
+	}
+	return cuessz.SnappyFramed
+}
+
+// formatValue renders a value as indented JSON or as YAML
+func formatValue(schema *cuessz.Schema, def string, value cuessz.Value, format string) ([]byte, error) {
+	if format == "yaml" {
+		return schema.MarshalValueYAML(def, value)
+	}
+	out, err := schema.MarshalValueJSON(def, value)
+	if err != nil {
+		return nil, err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, out, "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gfx-labs/cuessz"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	framed, err := cuessz.CompressSnappy([]byte{1, 2, 3}, cuessz.SnappyFramed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"hex", []byte("0x0102ff"), "hex"},
+		{"hex with whitespace", []byte("  0xAbCd\n"), "hex"},
+		{"empty hex", []byte("0x"), "hex"},
+		{"snappy", framed, "snappy"},
+		{"raw", []byte{1, 2, 3}, "raw"},
+		{"raw starting with 0x", []byte{'0', 'x', 0x01, 0xff}, "raw"},
+		{"odd hex digits", []byte("0x012"), "raw"},
+		{"hex then garbage", []byte("0x0102zz"), "raw"},
+		{"hex without prefix", []byte("0102"), "raw"},
+		{"empty", nil, "raw"},
+	}
+	for _, tt := range tests {
+		if got := detectEncoding(tt.data); got != tt.want {
+			t.Errorf("%s: detectEncoding(%q) = %s, want %s", tt.name, tt.data, got, tt.want)
+		}
+	}
+}
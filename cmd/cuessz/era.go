@@ -0,0 +1,239 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/gfx-labs/cuessz/era"
+)
+
+func eraCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz era ls|cat [flags] ...\n")
+		return 1
+	}
+
+	switch args[0] {
+	case "ls":
+		return eraLsCommand(args[1:])
+	case "cat":
+		return eraCatCommand(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown era command %q (must be ls or cat)\n", args[0])
+		return 1
+	}
+}
+
+// forkFlags holds the flags selecting the fork schedule of an era file
+type forkFlags struct {
+	forks         string
+	slotsPerEpoch uint64
+}
+
+func addForkFlags(fs *flag.FlagSet) *forkFlags {
+	f := &forkFlags{}
+	fs.StringVar(&f.forks, "forks", "", "fork schedule as name=epoch,... (default mainnet)")
+	fs.Uint64Var(&f.slotsPerEpoch, "slots-per-epoch", 32, "slots per epoch, used with -forks")
+	return f
+}
+
+func (f *forkFlags) schedule() (era.ForkSchedule, error) {
+	if f.forks == "" {
+		return era.Mainnet, nil
+	}
+	return era.ParseForkSchedule(f.forks, f.slotsPerEpoch)
+}
+
+func eraLsCommand(args []string) int {
+	fs := flag.NewFlagSet("era ls", flag.ExitOnError)
+	schemaFile := fs.String("schema", "", "schema file, to show the def each block and state decodes with")
+	forks := addForkFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz era ls [flags] <file.era>\n\n")
+		fmt.Fprintf(os.Stderr, "Lists the records of an era or era1 file.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 1
+	}
+	file := fs.Arg(0)
+
+	schedule, err := forks.schedule()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	var decoder *era.Decoder
+	if *schemaFile != "" {
+		schema, err := loadSchema(*schemaFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		decoder = &era.Decoder{Schema: schema, Forks: schedule}
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "OFFSET\tTYPE\tLENGTH\tSLOT\tFORK\tDEF\n")
+	r := era.NewReader(f)
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			w.Flush()
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			return 1
+		}
+
+		slot, fork, def := "", "", ""
+		switch rec.Type {
+		case era.TypeCompressedSignedBeaconBlock, era.TypeCompressedBeaconState:
+			n, err := era.Slot(rec)
+			if err != nil {
+				w.Flush()
+				fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+				return 1
+			}
+			slot, fork = fmt.Sprint(n), schedule.At(n)
+			if decoder != nil {
+				if def, err = decoder.Def(rec.Type, fork); err != nil {
+					def = "?"
+				}
+			}
+		case era.TypeSlotIndex:
+			if index, err := era.ParseSlotIndex(rec.Data); err == nil {
+				slot = fmt.Sprintf("%d+%d", index.StartSlot, len(index.Offsets))
+			}
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n", rec.Offset, rec.Type, len(rec.Data), slot, fork, def)
+	}
+	w.Flush()
+	return 0
+}
+
+func eraCatCommand(args []string) int {
+	fs := flag.NewFlagSet("era cat", flag.ExitOnError)
+	slot := fs.Int64("slot", -1, "only print the entries at this slot")
+	kind := fs.String("type", "all", "entries to print: block, state or all")
+	output := fs.String("output", "json", "output format: json or yaml")
+	forks := addForkFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz era cat [flags] <schema.json> <file.era>\n\n")
+		fmt.Fprintf(os.Stderr, "Decodes the blocks and states of an era file, using the defs\n")
+		fmt.Fprintf(os.Stderr, "SignedBeaconBlock<Fork> and BeaconState<Fork> (or SignedBeaconBlock\n")
+		fmt.Fprintf(os.Stderr, "and BeaconState) for the fork active at each slot. era1 files hold RLP\n")
+		fmt.Fprintf(os.Stderr, "execution data rather than SSZ, so they can only be listed with era ls.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 1
+	}
+	file := fs.Arg(1)
+
+	var types []era.RecordType
+	switch *kind {
+	case "block":
+		types = []era.RecordType{era.TypeCompressedSignedBeaconBlock}
+	case "state":
+		types = []era.RecordType{era.TypeCompressedBeaconState}
+	case "all":
+		types = []era.RecordType{era.TypeCompressedSignedBeaconBlock, era.TypeCompressedBeaconState}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown entry type %q (must be block, state or all)\n", *kind)
+		return 1
+	}
+	if *output != "json" && *output != "yaml" {
+		fmt.Fprintf(os.Stderr, "Error: unknown output format %q (must be json or yaml)\n", *output)
+		return 1
+	}
+
+	schedule, err := forks.schedule()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	schema, err := loadSchema(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	decoder := &era.Decoder{Schema: schema, Forks: schedule}
+
+	f, err := os.Open(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	printed := 0
+	r := era.NewReader(f)
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			return 1
+		}
+		if rec.Type.IsEra1() {
+			fmt.Fprintf(os.Stderr, "❌ %s: %s record at offset %d: era1 files hold RLP execution data, not SSZ (use era ls to list them)\n", file, rec.Type, rec.Offset)
+			return 1
+		}
+		if !slices.Contains(types, rec.Type) {
+			continue
+		}
+		if *slot >= 0 {
+			n, err := era.Slot(rec)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+				return 1
+			}
+			if n != uint64(*slot) {
+				continue
+			}
+		}
+
+		entry, err := decoder.Decode(rec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			return 1
+		}
+		out, err := formatValue(schema, entry.Def, entry.Value, *output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: slot %d: %v\n", file, entry.Slot, err)
+			return 1
+		}
+		if *output == "yaml" && printed > 0 {
+			fmt.Println("---")
+		}
+		os.Stdout.Write(out)
+		printed++
+	}
+
+	if printed == 0 {
+		fmt.Fprintf(os.Stderr, "❌ %s: no matching block or state records\n", file)
+		return 1
+	}
+	return 0
+}
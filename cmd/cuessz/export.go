@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func exportCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz export jsonschema [flags] <file>\n")
+		return 1
+	}
+
+	switch args[0] {
+	case "jsonschema":
+		return exportJSONSchemaCommand(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown export format %q (must be jsonschema)\n", args[0])
+		return 1
+	}
+}
+
+func exportJSONSchemaCommand(args []string) int {
+	flags := flag.NewFlagSet("export jsonschema", flag.ExitOnError)
+	root := flags.String("root", "", "def the document validates (default: only $defs)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz export jsonschema [flags] <file>\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	schema, err := loadSchema(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	doc, err := schema.JSONSchema(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s: %v\n", flags.Arg(0), err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gfx-labs/cuessz"
+)
+
+func fmtCommand(args []string) int {
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	output := addOutputFlags(fs, "json")
+	write := fs.Bool("w", false, "write the result back to the source file instead of stdout (json format only)")
+	check := fs.Bool("check", false, "report files that are not in canonical form and exit 1 (json format only)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz fmt [flags] <file1> [file2] ...\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	files := fs.Args()
+	if len(files) == 0 {
+		fs.Usage()
+		return 1
+	}
+	if *output.format != "json" && *output.format != "cue" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (must be json or cue)\n", *output.format)
+		return 1
+	}
+	if (*write || *check) && *output.format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: -w and -check only support the json format\n")
+		return 1
+	}
+
+	totalErrors := 0
+	for _, file := range files {
+		data, displayName, err := readSchemaFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", displayName, err)
+			totalErrors++
+			continue
+		}
+
+		schema, err := cuessz.ParseJSON(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", displayName, err)
+			totalErrors++
+			continue
+		}
+
+		out, err := output.render(schema)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", displayName, err)
+			totalErrors++
+			continue
+		}
+
+		switch {
+		case *check:
+			if !bytes.Equal(data, out) {
+				fmt.Printf("%s\n", displayName)
+				totalErrors++
+			}
+		case *write && file != "-":
+			if !bytes.Equal(data, out) {
+				if err := os.WriteFile(file, out, 0o644); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %s: failed to write file: %v\n", displayName, err)
+					totalErrors++
+				}
+			}
+		default:
+			os.Stdout.Write(out)
+		}
+	}
+
+	if totalErrors > 0 {
+		return 1
+	}
+	return 0
+}
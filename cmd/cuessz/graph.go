@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gfx-labs/cuessz"
+)
+
+func graphCommand(args []string) int {
+	flags := flag.NewFlagSet("graph", flag.ExitOnError)
+	format := flags.String("format", "dot", "output format: dot, mermaid or json")
+	root := flags.String("root", "", "only include defs reachable from this def")
+	fields := flags.Bool("fields", false, "label edges with the field holding the ref")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz graph [flags] <file>\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	data, name, err := readSchemaFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s: %v\n", name, err)
+		return 1
+	}
+
+	graph, err := cuessz.GraphJSON(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s: %v\n", name, err)
+		return 1
+	}
+	if *root != "" {
+		graph, err = graph.Subgraph(*root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", name, err)
+			return 1
+		}
+	}
+	if !*fields {
+		graph = graph.WithoutFields()
+	}
+
+	switch *format {
+	case "dot":
+		err = graph.WriteDOT(os.Stdout)
+	case "mermaid":
+		err = graph.WriteMermaid(os.Stdout)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(graph)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (must be dot, mermaid or json)\n", *format)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
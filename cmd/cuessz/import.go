@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/gfx-labs/cuessz"
+)
+
+func importCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz import go|pyspec [flags] ...\n")
+		return 1
+	}
+
+	switch args[0] {
+	case "go":
+		return importGoCommand(args[1:])
+	case "pyspec":
+		return importPySpecCommand(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown import source %q (must be go or pyspec)\n", args[0])
+		return 1
+	}
+}
+
+func importGoCommand(args []string) int {
+	fs := flag.NewFlagSet("import go", flag.ExitOnError)
+	var types stringList
+	fs.Var(&types, "type", "struct type to import (repeatable, default all exported structs)")
+	out := addOutputFlags(fs, "json")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz import go [flags] <dir>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 1
+	}
+	dir := fs.Arg(0)
+
+	schema, err := cuessz.FromGoPackage(dir, types...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s: %v\n", dir, err)
+		return 1
+	}
+
+	if err := out.write(schema); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s: %v\n", dir, err)
+		return 1
+	}
+	return 0
+}
+
+func importPySpecCommand(args []string) int {
+	fs := flag.NewFlagSet("import pyspec", flag.ExitOnError)
+	var presets stringList
+	fs.Var(&presets, "preset", "preset YAML file overriding the constants in the markdown (repeatable)")
+	out := addOutputFlags(fs, "cue")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz import pyspec [flags] <file.md> ...\n\n")
+		fmt.Fprintf(os.Stderr, "Files are read in order, so list forks oldest first:\n")
+		fmt.Fprintf(os.Stderr, "  cuessz import pyspec specs/phase0/beacon-chain.md specs/altair/beacon-chain.md\n\n")
+		fmt.Fprintf(os.Stderr, "Constants can be overridden with a preset such as\n")
+		fmt.Fprintf(os.Stderr, "-preset specs/consensus/presets/minimal.yaml from this repository\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 1
+	}
+
+	preset := cuessz.Preset{}
+	for _, file := range presets {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			return 1
+		}
+		p, err := cuessz.ParsePreset(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			return 1
+		}
+		maps.Copy(preset, p)
+	}
+
+	var docs [][]byte
+	for _, file := range fs.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			return 1
+		}
+		docs = append(docs, data)
+	}
+
+	schema, err := cuessz.ParsePySpec(docs, preset)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	if err := out.write(schema); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
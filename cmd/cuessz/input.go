@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gfx-labs/cuessz"
+)
+
+// readSchemaFile reads a JSON schema from a file, or from stdin when file is "-"
+// It returns the data along with the name to use for the file in messages
+func readSchemaFile(file string) ([]byte, string, error) {
+	if file == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, "stdin", fmt.Errorf("failed to read: %w", err)
+		}
+		return data, "stdin", nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(file))
+	if ext != ".json" {
+		return nil, file, fmt.Errorf("unsupported file type (must be .json)")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, file, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, file, nil
+}
+
+// loadSchema reads and validates a JSON schema file (or stdin when file is "-")
+func loadSchema(file string) (*cuessz.Schema, error) {
+	data, name, err := readSchemaFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	schema, err := cuessz.ParseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return schema, nil
+}
+
+// outputFlags are the flags of commands that print a schema
+type outputFlags struct {
+	format *string
+	pkg    *string
+	name   *string
+	short  *bool
+}
+
+func addOutputFlags(fs *flag.FlagSet, defaultFormat string) *outputFlags {
+	return &outputFlags{
+		format: fs.String("format", defaultFormat, "output format: json or cue"),
+		pkg:    fs.String("package", "schema", "CUE package name (cue format only)"),
+		name:   fs.String("name", "Schema", "CUE field holding the schema (cue format only)"),
+		short:  fs.Bool("short", false, "write uint8 vectors and lists as bytevector/bytelist"),
+	}
+}
+
+// write prints the schema to stdout in canonical form
+func (o *outputFlags) write(schema *cuessz.Schema) error {
+	out, err := o.render(schema)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+// render normalizes the schema and formats it as selected by the flags
+func (o *outputFlags) render(schema *cuessz.Schema) ([]byte, error) {
+	schema.Normalize()
+	if *o.short {
+		schema.CollapseShorthand()
+	}
+
+	switch *o.format {
+	case "cue":
+		return schema.FormatCUE(*o.pkg, *o.name)
+	case "json":
+		return schema.FormatJSON()
+	default:
+		return nil, fmt.Errorf("unknown format %q (must be json or cue)", *o.format)
+	}
+}
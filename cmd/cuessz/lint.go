@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/gfx-labs/cuessz"
+)
+
+// defaultConfigFile is read from the working directory when -config is not given
+const defaultConfigFile = ".cuessz.yaml"
+
+func lintCommand(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to the lint configuration (default ./"+defaultConfigFile+" if present)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz lint [-config file] <file1> [file2] ...\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	files := flags.Args()
+	if len(files) == 0 {
+		flags.Usage()
+		return 1
+	}
+
+	cfg, err := loadLintConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	totalErrors := 0
+	for _, file := range files {
+		schema, err := loadSchema(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			totalErrors++
+			continue
+		}
+
+		issues, err := cuessz.Lint(schema, cuessz.DefaultLinters(), cfg.Lint)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", file, issue)
+			if issue.Severity == cuessz.SeverityError {
+				totalErrors++
+			}
+		}
+	}
+
+	if totalErrors > 0 {
+		return 1
+	}
+	return 0
+}
+
+// loadLintConfig loads the given config file, or the default one if it exists
+func loadLintConfig(path string) (*cuessz.Config, error) {
+	if path == "" {
+		cfg, err := cuessz.LoadConfig(defaultConfigFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			return &cuessz.Config{}, nil
+		}
+		return cfg, err
+	}
+	return cuessz.LoadConfig(path)
+}
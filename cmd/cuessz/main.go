@@ -1,10 +1,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"io"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/gfx-labs/cuessz"
@@ -21,10 +20,28 @@ func main() {
 	switch command {
 	case "vet":
 		if len(os.Args) < 3 {
-			fmt.Fprintf(os.Stderr, "Usage: cuessz vet <file1> [file2] ...\n")
+			fmt.Fprintf(os.Stderr, "Usage: cuessz vet [-root Name] ... <file1> [file2] ...\n")
 			os.Exit(1)
 		}
 		os.Exit(vetCommand(os.Args[2:]))
+	case "fmt":
+		os.Exit(fmtCommand(os.Args[2:]))
+	case "lint":
+		os.Exit(lintCommand(os.Args[2:]))
+	case "graph":
+		os.Exit(graphCommand(os.Args[2:]))
+	case "decode":
+		os.Exit(decodeCommand(os.Args[2:]))
+	case "encode":
+		os.Exit(encodeCommand(os.Args[2:]))
+	case "root":
+		os.Exit(rootCommand(os.Args[2:]))
+	case "era":
+		os.Exit(eraCommand(os.Args[2:]))
+	case "export":
+		os.Exit(exportCommand(os.Args[2:]))
+	case "import":
+		os.Exit(importCommand(os.Args[2:]))
 	case "help", "-h", "--help":
 		printUsage()
 		os.Exit(0)
@@ -41,6 +58,18 @@ func printUsage() {
 Usage:
   cuessz vet <file1> [file2] ...    Validate JSON schema files
   cuessz vet -                      Read JSON schema from stdin
+  cuessz vet -root Name <file>      Also warn about defs unreachable from Name
+  cuessz fmt [flags] <file> ...     Print schema files in canonical form
+  cuessz lint [-config f] <file>    Check schema conventions (see .cuessz.yaml)
+  cuessz graph [flags] <file>       Print the def reference graph (dot, mermaid, json)
+  cuessz decode <file> <Def> [in]   Print SSZ bytes (raw, hex or snappy) as JSON
+  cuessz encode <file> <Def> [in]   Print a JSON value as SSZ bytes
+  cuessz root <file> <Def> [in]     Print the hash_tree_root of SSZ bytes
+  cuessz era ls <file.era>          List the records of an era or era1 file
+  cuessz era cat <file> <f.era>     Print the blocks and states of an era file
+  cuessz export jsonschema <file>   Print a JSON Schema for the JSON form of values
+  cuessz import go [flags] <dir>    Derive a schema from fastssz-tagged Go structs
+  cuessz import pyspec <f.md> ...   Derive a schema from consensus-specs markdown (as CUE)
   cuessz help                       Show this help message
 
 Examples:
@@ -49,13 +78,33 @@ Examples:
   cuessz vet -                      Validate JSON from stdin
   cat schema.json | cuessz vet -    Pipe JSON to validator
   cue export schema.cue | cuessz vet -    Export CUE to JSON and validate
+  cuessz fmt -w schema.json         Rewrite a JSON schema in canonical form
+  cuessz fmt -format cue -short -package zoo -name Zoo zoo.json
+                                    Print a schema as compact CUE
+  cuessz graph -root BeaconStateCapella -fields -format mermaid consensus.json
+                                    Show what a def depends on
+  cuessz decode -input snappy consensus.json SignedBeaconBlock block.sz
+                                    Print a snappy framed block as JSON
+  cuessz root -fields consensus.json BeaconBlockHeader header.ssz
+                                    Show a header's body_root next to its other fields
+  cuessz import go -type BeaconState -short ./types
+                                    Print a Go struct and its dependencies as a schema
+
+Warnings (unused or structurally identical defs) are printed but do not fail vet.
+Unused defs are reported when roots are given with -root or in the schema's roots.
 
 Exit codes:
   0 - All files valid
   1 - Validation errors found or usage error`)
 }
 
-func vetCommand(files []string) int {
+func vetCommand(args []string) int {
+	fs := flag.NewFlagSet("vet", flag.ExitOnError)
+	var roots stringList
+	fs.Var(&roots, "root", "entry-point def for unused def detection (repeatable)")
+	fs.Parse(args)
+
+	files := fs.Args()
 	if len(files) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: no files specified\n")
 		return 1
@@ -67,42 +116,24 @@ func vetCommand(files []string) int {
 	for _, file := range files {
 		totalFiles++
 
-		var err error
-		var data []byte
-
-		if file == "-" {
-			// Read from stdin
-			data, err = io.ReadAll(os.Stdin)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "❌ stdin: failed to read: %v\n", err)
-				totalErrors++
-				continue
-			}
-			file = "stdin"
-		} else {
-			// Read from file
-			ext := strings.ToLower(filepath.Ext(file))
-			if ext != ".json" {
-				fmt.Fprintf(os.Stderr, "❌ %s: unsupported file type (must be .json)\n", file)
-				totalErrors++
-				continue
-			}
-
-			data, err = os.ReadFile(file)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "❌ %s: failed to read file: %v\n", file, err)
-				totalErrors++
-				continue
-			}
+		data, name, err := readSchemaFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", name, err)
+			totalErrors++
+			continue
 		}
+		file = name
 
 		// Validate the JSON data
-		_, err = cuessz.ParseJSON(data)
+		_, warnings, err := cuessz.VetJSON(data, cuessz.VetOptions{Roots: roots})
 		if err != nil {
 			fmt.Printf("❌ %s: %v\n", file, err)
 			totalErrors++
 		} else {
 			fmt.Printf("✓ %s: valid\n", file)
+			for _, w := range warnings {
+				fmt.Printf("⚠ %s: %s\n", file, w)
+			}
 		}
 	}
 
@@ -116,3 +147,14 @@ func vetCommand(files []string) int {
 	}
 }
 
+// stringList is a flag.Value collecting every occurrence of a repeatable flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func rootCommand(args []string) int {
+	flags := flag.NewFlagSet("root", flag.ExitOnError)
+	input := flags.String("input", "auto", "input encoding: auto, raw, hex, snappy (framed) or snappy-block")
+	fields := flags.Bool("fields", false, "also print the root of each top-level field")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: cuessz root [flags] <schema.json> <Def> [file]\n\n")
+		fmt.Fprintf(os.Stderr, "Reads SSZ bytes from file (or stdin) and prints the hash_tree_root of Def.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 || flags.NArg() > 3 {
+		flags.Usage()
+		return 1
+	}
+	schema, err := loadSchema(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	def := flags.Arg(1)
+
+	raw, err := readInput(flags.Arg(2))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	data, err := decodeInput(schema, def, raw, *input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	value, err := schema.UnmarshalSSZ(def, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	root, err := schema.HashTreeRoot(def, value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	if !*fields {
+		fmt.Println(root)
+		return 0
+	}
+
+	fieldRoots, err := schema.FieldRoots(def, value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\n", def, root)
+	for _, field := range fieldRoots {
+		fmt.Fprintf(w, "  %s\t%s\n", field.Name, field.Root)
+	}
+	w.Flush()
+	return 0
+}
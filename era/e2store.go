@@ -0,0 +1,169 @@
+// Package era reads .era and .era1 archive files: e2store records holding
+// snappy-compressed SSZ blocks and states, decoded with the defs of a cuessz.Schema
+package era
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// headerSize is the size of an e2store record header: type, length and reserved bytes
+const headerSize = 8
+
+// RecordType is the type of an e2store record, read little-endian from its first two bytes
+type RecordType uint16
+
+const (
+	TypeEmpty                       RecordType = 0x0000
+	TypeCompressedSignedBeaconBlock RecordType = 0x0001
+	TypeCompressedBeaconState       RecordType = 0x0002
+	TypeVersion                     RecordType = 0x3265 // "e2"
+	TypeSlotIndex                   RecordType = 0x3269 // "i2"
+
+	// era1 records hold RLP encoded execution data rather than SSZ
+	TypeCompressedHeader   RecordType = 0x0003
+	TypeCompressedBody     RecordType = 0x0004
+	TypeCompressedReceipts RecordType = 0x0005
+	TypeTotalDifficulty    RecordType = 0x0006
+	TypeAccumulator        RecordType = 0x0007
+	TypeBlockIndex         RecordType = 0x3266 // "f2"
+)
+
+var recordTypeNames = map[RecordType]string{
+	TypeEmpty:                       "Empty",
+	TypeCompressedSignedBeaconBlock: "CompressedSignedBeaconBlock",
+	TypeCompressedBeaconState:       "CompressedBeaconState",
+	TypeVersion:                     "Version",
+	TypeSlotIndex:                   "SlotIndex",
+	TypeCompressedHeader:            "CompressedHeader",
+	TypeCompressedBody:              "CompressedBody",
+	TypeCompressedReceipts:          "CompressedReceipts",
+	TypeTotalDifficulty:             "TotalDifficulty",
+	TypeAccumulator:                 "Accumulator",
+	TypeBlockIndex:                  "BlockIndex",
+}
+
+func (t RecordType) String() string {
+	if name, ok := recordTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown(0x%04x)", uint16(t))
+}
+
+// IsEra1 reports whether records of the type only occur in era1 files
+func (t RecordType) IsEra1() bool {
+	switch t {
+	case TypeCompressedHeader, TypeCompressedBody, TypeCompressedReceipts, TypeTotalDifficulty, TypeAccumulator, TypeBlockIndex:
+		return true
+	}
+	return false
+}
+
+// Record is one e2store record
+type Record struct {
+	Type RecordType
+	// Offset is the position of the record header in the file
+	Offset int64
+	Data   []byte
+}
+
+// Reader reads e2store records in file order
+type Reader struct {
+	r      io.Reader
+	offset int64
+}
+
+// NewReader returns a Reader reading records from r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Next returns the next record, or io.EOF after the last one
+func (r *Reader) Next() (*Record, error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(r.r, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("record at offset %d: truncated header", r.offset)
+		}
+		return nil, err
+	}
+
+	rec := &Record{
+		Type:   RecordType(binary.LittleEndian.Uint16(header[0:2])),
+		Offset: r.offset,
+	}
+	length := binary.LittleEndian.Uint32(header[2:6])
+	if reserved := binary.LittleEndian.Uint16(header[6:8]); reserved != 0 {
+		return nil, fmt.Errorf("record at offset %d: reserved bytes are 0x%04x, must be zero", r.offset, reserved)
+	}
+
+	// Read through a limit instead of allocating the claimed length up front
+	data, err := io.ReadAll(io.LimitReader(r.r, int64(length)))
+	if err != nil {
+		return nil, fmt.Errorf("record at offset %d: %w", r.offset, err)
+	}
+	if uint32(len(data)) != length {
+		return nil, fmt.Errorf("record at offset %d: %s claims %d bytes, file ends after %d", r.offset, rec.Type, length, len(data))
+	}
+	rec.Data = data
+	r.offset += headerSize + int64(length)
+	return rec, nil
+}
+
+// ReadAll reads all remaining records
+func (r *Reader) ReadAll() ([]*Record, error) {
+	var records []*Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// WriteRecord writes one e2store record
+func WriteRecord(w io.Writer, typ RecordType, data []byte) error {
+	if uint64(len(data)) > 1<<32-1 {
+		return fmt.Errorf("%s record of %d bytes does not fit the 4-byte length", typ, len(data))
+	}
+	var header [headerSize]byte
+	binary.LittleEndian.PutUint16(header[0:2], uint16(typ))
+	binary.LittleEndian.PutUint32(header[2:6], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// SlotIndex is the index record of an era file: the start slot and, per slot,
+// the offset of its block record relative to the index record (0 for empty slots)
+type SlotIndex struct {
+	StartSlot uint64
+	Offsets   []int64
+}
+
+// ParseSlotIndex parses the data of a SlotIndex record
+func ParseSlotIndex(data []byte) (*SlotIndex, error) {
+	if len(data) < 16 || len(data)%8 != 0 {
+		return nil, fmt.Errorf("slot index of %d bytes is malformed", len(data))
+	}
+	count := binary.LittleEndian.Uint64(data[len(data)-8:])
+	if count != uint64(len(data)/8-2) {
+		return nil, fmt.Errorf("slot index claims %d entries but holds %d", count, len(data)/8-2)
+	}
+	index := &SlotIndex{
+		StartSlot: binary.LittleEndian.Uint64(data[0:8]),
+		Offsets:   make([]int64, count),
+	}
+	for i := range index.Offsets {
+		index.Offsets[i] = int64(binary.LittleEndian.Uint64(data[8+8*i:]))
+	}
+	return index, nil
+}
@@ -0,0 +1,235 @@
+package era
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gfx-labs/cuessz"
+	"github.com/golang/snappy"
+)
+
+// Byte offsets of the slot in SSZ data, the same in every fork: a SignedBeaconBlock
+// is an offset to its message and a 96-byte signature followed by the message,
+// whose first field is the slot; a BeaconState starts with genesis_time and
+// genesis_validators_root
+const (
+	blockMessageOffset = 4 + 96
+	stateSlotOffset    = 8 + 32
+)
+
+// Fork is a named fork and the epoch it activates at
+type Fork struct {
+	Name  string
+	Epoch uint64
+}
+
+// ForkSchedule maps slots to forks
+type ForkSchedule struct {
+	SlotsPerEpoch uint64
+	// Forks are ordered by activation epoch
+	Forks []Fork
+}
+
+// Mainnet is the mainnet fork schedule
+var Mainnet = ForkSchedule{
+	SlotsPerEpoch: 32,
+	Forks: []Fork{
+		{"phase0", 0},
+		{"altair", 74240},
+		{"bellatrix", 144896},
+		{"capella", 194048},
+		{"deneb", 269568},
+		{"electra", 364032},
+		{"fulu", 411392},
+	},
+}
+
+// ParseForkSchedule parses forks as comma-separated name=epoch pairs, e.g.
+// "phase0=0,altair=50", with the given slots per epoch
+func ParseForkSchedule(s string, slotsPerEpoch uint64) (ForkSchedule, error) {
+	schedule := ForkSchedule{SlotsPerEpoch: slotsPerEpoch}
+	for _, pair := range strings.Split(s, ",") {
+		name, epoch, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" {
+			return ForkSchedule{}, fmt.Errorf("fork %q must be name=epoch", pair)
+		}
+		n, err := strconv.ParseUint(epoch, 10, 64)
+		if err != nil {
+			return ForkSchedule{}, fmt.Errorf("fork %q: invalid epoch: %w", pair, err)
+		}
+		schedule.Forks = append(schedule.Forks, Fork{Name: name, Epoch: n})
+	}
+	slices.SortStableFunc(schedule.Forks, func(a, b Fork) int {
+		return cmp.Compare(a.Epoch, b.Epoch)
+	})
+	return schedule, nil
+}
+
+// At returns the name of the fork active at slot, or "" before the first fork
+func (f ForkSchedule) At(slot uint64) string {
+	epoch := slot / max(f.SlotsPerEpoch, 1)
+	name := ""
+	for _, fork := range f.Forks {
+		if fork.Epoch > epoch {
+			break
+		}
+		name = fork.Name
+	}
+	return name
+}
+
+// Entry is a decoded block or state record
+type Entry struct {
+	Slot uint64
+	Fork string
+	// Def is the schema def the entry was decoded with
+	Def   string
+	Value cuessz.Value
+}
+
+// Decoder decodes era records with defs named after the fork active at their slot:
+// SignedBeaconBlock<Fork> and BeaconState<Fork> (e.g. BeaconStateCapella). A fork
+// without its own def uses that of the latest earlier fork that has one, and the
+// unsuffixed SignedBeaconBlock and BeaconState stand for the first fork, or for
+// every fork when the schema has no suffixed defs
+type Decoder struct {
+	Schema *cuessz.Schema
+	Forks  ForkSchedule
+}
+
+// NewDecoder returns a Decoder using the mainnet fork schedule
+func NewDecoder(schema *cuessz.Schema) *Decoder {
+	return &Decoder{Schema: schema, Forks: Mainnet}
+}
+
+// Slot reads the slot of a block or state record, decompressing only the start of it
+func Slot(rec *Record) (uint64, error) {
+	_, slot, err := readHead(rec, snappy.NewReader(bytes.NewReader(rec.Data)))
+	return slot, err
+}
+
+// Decode decompresses and decodes a block or state record. Decompression is
+// bounded by the max size of the def picked for the record's slot
+func (d *Decoder) Decode(rec *Record) (*Entry, error) {
+	r := snappy.NewReader(bytes.NewReader(rec.Data))
+	head, slot, err := readHead(rec, r)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{Slot: slot, Fork: d.Forks.At(slot)}
+	if entry.Def, err = d.Def(rec.Type, entry.Fork); err != nil {
+		return nil, fmt.Errorf("%s at slot %d: %w", rec.Type, slot, err)
+	}
+	maxSize, err := d.Schema.MaxSizeSSZ(entry.Def)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the maximum to tell a full value from an oversized one
+	remaining := int64(math.MaxInt64 - 1)
+	if maxSize < math.MaxInt64 {
+		remaining = max(int64(maxSize)-int64(len(head)), 0)
+	}
+	rest, err := io.ReadAll(io.LimitReader(r, remaining+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s at slot %d: %w", entry.Def, slot, err)
+	}
+	data := append(head, rest...)
+	if uint64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%s at slot %d: decompresses to more than the maximum of %d bytes", entry.Def, slot, maxSize)
+	}
+
+	if entry.Value, err = d.Schema.UnmarshalSSZ(entry.Def, data); err != nil {
+		return nil, fmt.Errorf("slot %d: %w", slot, err)
+	}
+	return entry, nil
+}
+
+// Def returns the def used for records of typ in fork
+func (d *Decoder) Def(typ RecordType, fork string) (string, error) {
+	var base string
+	switch typ {
+	case TypeCompressedSignedBeaconBlock:
+		base = "SignedBeaconBlock"
+	case TypeCompressedBeaconState:
+		base = "BeaconState"
+	default:
+		return "", fmt.Errorf("%s records are not SSZ blocks or states", typ)
+	}
+
+	if fork == "" {
+		return d.lookup(base)
+	}
+	at := slices.IndexFunc(d.Forks.Forks, func(f Fork) bool { return f.Name == fork })
+	if at < 0 {
+		return "", fmt.Errorf("fork %s is not in the fork schedule", fork)
+	}
+
+	// Forks after the last one with a def of their own may have changed the type,
+	// so they are not decoded with an earlier def
+	last := -1
+	for i, f := range d.Forks.Forks {
+		if _, ok := d.Schema.Defs[base+forkSuffix(f.Name)]; ok {
+			last = i
+		}
+	}
+	if last < 0 {
+		return d.lookup(base)
+	}
+	if at > last {
+		return "", fmt.Errorf("schema has no def %s%s, and %s is after %s, the last fork with a def", base, forkSuffix(fork), fork, d.Forks.Forks[last].Name)
+	}
+	for i := at; i >= 0; i-- {
+		name := base + forkSuffix(d.Forks.Forks[i].Name)
+		if _, ok := d.Schema.Defs[name]; ok {
+			return name, nil
+		}
+	}
+	return d.lookup(base)
+}
+
+// lookup returns name if the schema defines it
+func (d *Decoder) lookup(name string) (string, error) {
+	if _, ok := d.Schema.Defs[name]; !ok {
+		return "", fmt.Errorf("schema has no def %s", name)
+	}
+	return name, nil
+}
+
+// forkSuffix is the def name suffix of a fork, e.g. Capella for capella
+func forkSuffix(fork string) string {
+	return strings.ToUpper(fork[:1]) + fork[1:]
+}
+
+// readHead decompresses the start of a block or state record, up to and
+// including its slot
+func readHead(rec *Record, r io.Reader) ([]byte, uint64, error) {
+	var at int
+	switch rec.Type {
+	case TypeCompressedSignedBeaconBlock:
+		at = blockMessageOffset
+	case TypeCompressedBeaconState:
+		at = stateSlotOffset
+	default:
+		return nil, 0, fmt.Errorf("%s at offset %d is not a block or state record", rec.Type, rec.Offset)
+	}
+
+	head := make([]byte, at+8)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, 0, fmt.Errorf("%s at offset %d: reading the slot: %w", rec.Type, rec.Offset, err)
+	}
+	if rec.Type == TypeCompressedSignedBeaconBlock {
+		if offset := binary.LittleEndian.Uint32(head); offset != blockMessageOffset {
+			return nil, 0, fmt.Errorf("%s at offset %d: message offset is %d, expected %d", rec.Type, rec.Offset, offset, blockMessageOffset)
+		}
+	}
+	return head, binary.LittleEndian.Uint64(head[at:]), nil
+}
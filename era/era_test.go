@@ -0,0 +1,224 @@
+package era
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gfx-labs/cuessz"
+)
+
+func testSchema() *cuessz.Schema {
+	return cuessz.NewSchema().
+		Def("Signature", cuessz.ByteVector(96)).
+		Add(
+			cuessz.Container("BeaconBlock").
+				Field("slot", cuessz.Uint64()).
+				Field("graffiti", cuessz.ByteList(32)),
+			cuessz.Container("BeaconBlockAltair").
+				Field("slot", cuessz.Uint64()).
+				Field("graffiti", cuessz.ByteList(32)).
+				Field("sync_bits", cuessz.BitVector(8)),
+			cuessz.Container("SignedBeaconBlock").
+				Field("message", cuessz.Ref("BeaconBlock")).
+				Field("signature", cuessz.Ref("Signature")),
+			cuessz.Container("SignedBeaconBlockAltair").
+				Field("message", cuessz.Ref("BeaconBlockAltair")).
+				Field("signature", cuessz.Ref("Signature")),
+			cuessz.Container("BeaconState").
+				Field("genesis_time", cuessz.Uint64()).
+				Field("genesis_validators_root", cuessz.ByteVector(32)).
+				Field("slot", cuessz.Uint64()),
+		).
+		MustBuild()
+}
+
+// compressed encodes a value and compresses it the way era records are
+func compressed(t *testing.T, schema *cuessz.Schema, def string, v cuessz.Value) []byte {
+	t.Helper()
+	data, err := schema.MarshalSSZSnappy(def, v, cuessz.SnappyFramed)
+	if err != nil {
+		t.Fatalf("MarshalSSZSnappy(%s) failed: %v", def, err)
+	}
+	return data
+}
+
+func block(slot uint64, altair bool) map[string]any {
+	message := map[string]any{"slot": slot, "graffiti": []byte("hi")}
+	if altair {
+		message["sync_bits"] = make([]bool, 8)
+	}
+	return map[string]any{"message": message, "signature": make([]byte, 96)}
+}
+
+func TestDecoder(t *testing.T) {
+	schema := testSchema()
+	forks, err := ParseForkSchedule("altair=2, phase0=0", 4)
+	if err != nil {
+		t.Fatalf("ParseForkSchedule failed: %v", err)
+	}
+
+	var file bytes.Buffer
+	write := func(typ RecordType, data []byte) {
+		if err := WriteRecord(&file, typ, data); err != nil {
+			t.Fatalf("WriteRecord failed: %v", err)
+		}
+	}
+	write(TypeVersion, nil)
+	write(TypeCompressedSignedBeaconBlock, compressed(t, schema, "SignedBeaconBlock", block(1, false)))
+	write(TypeCompressedSignedBeaconBlock, compressed(t, schema, "SignedBeaconBlockAltair", block(8, true)))
+	write(TypeCompressedBeaconState, compressed(t, schema, "BeaconState", map[string]any{
+		"genesis_time": uint64(0), "genesis_validators_root": make([]byte, 32), "slot": uint64(8),
+	}))
+	index := make([]byte, 8*4)
+	binary.LittleEndian.PutUint64(index[0:], 1)
+	binary.LittleEndian.PutUint64(index[24:], 2)
+	write(TypeSlotIndex, index)
+
+	records, err := NewReader(&file).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	var types []string
+	for _, rec := range records {
+		types = append(types, rec.Type.String())
+	}
+	if got := strings.Join(types, ","); got != "Version,CompressedSignedBeaconBlock,CompressedSignedBeaconBlock,CompressedBeaconState,SlotIndex" {
+		t.Fatalf("unexpected records: %s", got)
+	}
+	if records[1].Offset != 8 {
+		t.Errorf("record offset = %d, want 8", records[1].Offset)
+	}
+
+	decoder := &Decoder{Schema: schema, Forks: forks}
+	want := []struct {
+		slot uint64
+		fork string
+		def  string
+	}{
+		{1, "phase0", "SignedBeaconBlock"}, // no SignedBeaconBlockPhase0, so the fallback
+		{8, "altair", "SignedBeaconBlockAltair"},
+		{8, "altair", "BeaconState"},
+	}
+	for i, w := range want {
+		rec := records[i+1]
+		slot, err := Slot(rec)
+		if err != nil || slot != w.slot {
+			t.Errorf("Slot(record %d) = %d, %v; want %d", i+1, slot, err, w.slot)
+		}
+		entry, err := decoder.Decode(rec)
+		if err != nil {
+			t.Fatalf("Decode(record %d) failed: %v", i+1, err)
+		}
+		if entry.Slot != w.slot || entry.Fork != w.fork || entry.Def != w.def {
+			t.Errorf("Decode(record %d) = slot %d, %s, %s; want %d, %s, %s", i+1, entry.Slot, entry.Fork, entry.Def, w.slot, w.fork, w.def)
+		}
+	}
+
+	idx, err := ParseSlotIndex(records[4].Data)
+	if err != nil || idx.StartSlot != 1 || len(idx.Offsets) != 2 {
+		t.Errorf("ParseSlotIndex = %+v, %v", idx, err)
+	}
+	if _, err := decoder.Decode(records[0]); err == nil || !strings.Contains(err.Error(), "not a block or state") {
+		t.Errorf("expected error decoding a version record, got: %v", err)
+	}
+}
+
+func TestDecoder_Def(t *testing.T) {
+	schema := testSchema()
+	schema.Defs["SignedBeaconBlockCapella"] = schema.Defs["SignedBeaconBlockAltair"]
+	decoder := NewDecoder(schema)
+
+	tests := []struct {
+		typ  RecordType
+		fork string
+		want string
+	}{
+		{TypeCompressedSignedBeaconBlock, "", "SignedBeaconBlock"},
+		{TypeCompressedSignedBeaconBlock, "phase0", "SignedBeaconBlock"},
+		{TypeCompressedSignedBeaconBlock, "altair", "SignedBeaconBlockAltair"},
+		{TypeCompressedSignedBeaconBlock, "bellatrix", "SignedBeaconBlockAltair"},
+		{TypeCompressedSignedBeaconBlock, "capella", "SignedBeaconBlockCapella"},
+		// No state def is suffixed, so BeaconState is used for every fork
+		{TypeCompressedBeaconState, "fulu", "BeaconState"},
+	}
+	for _, tt := range tests {
+		if got, err := decoder.Def(tt.typ, tt.fork); err != nil || got != tt.want {
+			t.Errorf("Def(%s, %q) = %s, %v; want %s", tt.typ, tt.fork, got, err, tt.want)
+		}
+	}
+
+	errs := []struct {
+		typ  RecordType
+		fork string
+		want string
+	}{
+		{TypeCompressedSignedBeaconBlock, "deneb", "no def SignedBeaconBlockDeneb, and deneb is after capella"},
+		{TypeCompressedSignedBeaconBlock, "unknown", "fork unknown is not in the fork schedule"},
+		{TypeCompressedHeader, "phase0", "not SSZ blocks or states"},
+	}
+	for _, tt := range errs {
+		if _, err := decoder.Def(tt.typ, tt.fork); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Def(%s, %q): expected error containing %q, got: %v", tt.typ, tt.fork, tt.want, err)
+		}
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	schema := testSchema()
+	decoder := NewDecoder(schema)
+
+	// A state padded past the max size of BeaconState (48 bytes)
+	state, _ := cuessz.CompressSnappy(make([]byte, 64), cuessz.SnappyFramed)
+	if _, err := decoder.Decode(&Record{Type: TypeCompressedBeaconState, Data: state}); err == nil || !strings.Contains(err.Error(), "maximum of 48") {
+		t.Errorf("expected max size error, got: %v", err)
+	}
+
+	bad, _ := cuessz.CompressSnappy(make([]byte, 120), cuessz.SnappyFramed)
+	if _, err := decoder.Decode(&Record{Type: TypeCompressedSignedBeaconBlock, Data: bad}); err == nil || !strings.Contains(err.Error(), "message offset is 0") {
+		t.Errorf("expected message offset error, got: %v", err)
+	}
+
+	var file bytes.Buffer
+	WriteRecord(&file, TypeVersion, nil)
+	file.Write([]byte{0x01, 0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01})
+	r := NewReader(&file)
+	if _, err := r.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "claims 255 bytes") {
+		t.Errorf("expected truncated record error, got: %v", err)
+	}
+
+	reserved := []byte{0x65, 0x32, 0, 0, 0, 0, 1, 0}
+	if _, err := NewReader(bytes.NewReader(reserved)).Next(); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("expected reserved bytes error, got: %v", err)
+	}
+	if _, err := NewReader(bytes.NewReader(nil)).Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+func TestForkSchedule(t *testing.T) {
+	tests := []struct {
+		slot uint64
+		want string
+	}{
+		{0, "phase0"},
+		{74240*32 - 1, "phase0"},
+		{74240 * 32, "altair"},
+		{194048 * 32, "capella"},
+		{1 << 40, "fulu"},
+	}
+	for _, tt := range tests {
+		if got := Mainnet.At(tt.slot); got != tt.want {
+			t.Errorf("Mainnet.At(%d) = %s, want %s", tt.slot, got, tt.want)
+		}
+	}
+
+	if _, err := ParseForkSchedule("altair", 32); err == nil {
+		t.Error("expected error for a fork without an epoch")
+	}
+}
@@ -0,0 +1,155 @@
+package cuessz
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"maps"
+	"slices"
+)
+
+// Fingerprint is a content address for the structure of a def
+// Two defs share a fingerprint exactly when they encode and merkleize the same way,
+// so it can be stored next to encoded data to detect schema changes at read time
+type Fingerprint [32]byte
+
+func (f Fingerprint) String() string {
+	return hex.EncodeToString(f[:])
+}
+
+// Fingerprint hashes the wire- and merkle-relevant content of the def: types, sizes,
+// limits, active fields and child order, with refs expanded through refs
+// Descriptions, field names and def names do not contribute, and a ref hashes the
+// same as an inline copy of the def it points to
+func (d *Def) Fingerprint(refs map[string]Def) (Fingerprint, error) {
+	return newFingerprinter(refs).def(d)
+}
+
+// Fingerprint hashes the structure of every def in the schema, so it changes
+// whenever a type is added, removed or changes shape. Like Def.Fingerprint it
+// ignores names: the defs are hashed as a sorted set of their fingerprints, so
+// renaming a def leaves it unchanged. Descriptions, metadata and the version do
+// not contribute either
+func (s *Schema) Fingerprint() (Fingerprint, error) {
+	fp := newFingerprinter(s.Defs)
+
+	defs := make([]Fingerprint, 0, len(s.Defs))
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		f, err := fp.ref(name)
+		if err != nil {
+			return Fingerprint{}, fmt.Errorf("def '%s': %w", name, err)
+		}
+		defs = append(defs, f)
+	}
+	slices.SortFunc(defs, func(a, b Fingerprint) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	h := sha256.New()
+	writeString(h, "schema")
+	writeUint64(h, uint64(len(defs)))
+	for _, f := range defs {
+		h.Write(f[:])
+	}
+
+	var out Fingerprint
+	h.Sum(out[:0])
+	return out, nil
+}
+
+// fingerprinter memoizes def fingerprints by ref name and detects reference cycles
+type fingerprinter struct {
+	refs       map[string]Def
+	memo       map[string]Fingerprint
+	inProgress map[string]bool
+
+	// fieldNames additionally hashes field names, for comparisons where two
+	// containers with the same shape but different fields should stay distinct
+	fieldNames bool
+}
+
+func newFingerprinter(refs map[string]Def) *fingerprinter {
+	return &fingerprinter{
+		refs:       refs,
+		memo:       make(map[string]Fingerprint),
+		inProgress: make(map[string]bool),
+	}
+}
+
+// ref returns the fingerprint of the named def
+func (fp *fingerprinter) ref(name string) (Fingerprint, error) {
+	if f, ok := fp.memo[name]; ok {
+		return f, nil
+	}
+	if fp.inProgress[name] {
+		return Fingerprint{}, fmt.Errorf("%w: through '%s'", ErrRecursiveType, name)
+	}
+	def, ok := fp.refs[name]
+	if !ok {
+		return Fingerprint{}, fmt.Errorf("ref type '%s' not found", name)
+	}
+
+	fp.inProgress[name] = true
+	defer delete(fp.inProgress, name)
+
+	f, err := fp.def(&def)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	fp.memo[name] = f
+	return f, nil
+}
+
+// def returns the fingerprint of a def, hashing child fingerprints rather than
+// child content so that refs and inline defs are interchangeable
+func (fp *fingerprinter) def(d *Def) (Fingerprint, error) {
+	if d.Type == TypeRef {
+		return fp.ref(d.Ref)
+	}
+	if d.Type == TypeByteVector || d.Type == TypeByteList {
+		expanded := d.Clone()
+		expandShorthand(&expanded)
+		d = &expanded
+	}
+
+	h := sha256.New()
+	writeString(h, string(d.Type))
+	writeUint64(h, d.Size)
+	writeUint64(h, d.Limit)
+
+	writeUint64(h, uint64(len(d.ActiveFields)))
+	for _, active := range d.ActiveFields {
+		writeUint64(h, uint64(active))
+	}
+
+	writeUint64(h, uint64(len(d.Children)))
+	for _, child := range d.Children {
+		f, err := fp.def(&child.Def)
+		if err != nil {
+			return Fingerprint{}, fmt.Errorf("field '%s': %w", child.Name, err)
+		}
+		if fp.fieldNames {
+			writeString(h, child.Name)
+		}
+		h.Write(f[:])
+	}
+
+	var out Fingerprint
+	h.Sum(out[:0])
+	return out, nil
+}
+
+// writeString writes a length-prefixed string so adjacent values cannot run together
+func writeString(h hash.Hash, s string) {
+	writeUint64(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
+
+func writeUint64(h hash.Hash, v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
@@ -0,0 +1,129 @@
+package cuessz
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefFingerprint(t *testing.T) {
+	desc := "the epoch"
+	refs := map[string]Def{
+		"Root":  {Type: TypeByteVector, Size: 32},
+		"Bytes": {Type: TypeVector, Size: 32, Children: []Field{byteElement()}},
+	}
+
+	checkpoint := Def{
+		Type: TypeContainer,
+		Children: []Field{
+			{Name: "epoch", Def: Def{Type: TypeUint64}},
+			{Name: "root", Def: Def{Type: TypeRef, Ref: "Root"}},
+		},
+	}
+	fingerprint := func(d Def) Fingerprint {
+		t.Helper()
+		f, err := d.Fingerprint(refs)
+		if err != nil {
+			t.Fatalf("Fingerprint failed: %v", err)
+		}
+		return f
+	}
+	base := fingerprint(checkpoint)
+
+	// Descriptions, field names and ref names do not matter
+	renamed := checkpoint.Clone()
+	renamed.Description = &desc
+	renamed.Children[0].Name = "epoch_number"
+	renamed.Children[0].Description = &desc
+	renamed.Children[1].Def.Ref = "Bytes"
+	if got := fingerprint(renamed); got != base {
+		t.Errorf("expected descriptions and names not to change fingerprint")
+	}
+
+	// A ref hashes the same as the def it points to
+	inlined := checkpoint.Clone()
+	inlined.Children[1].Def = refs["Bytes"]
+	if got := fingerprint(inlined); got != base {
+		t.Errorf("expected inline def to match ref fingerprint")
+	}
+
+	// Structural changes do
+	resized := checkpoint.Clone()
+	resized.Children[0].Def.Type = TypeUint32
+	if got := fingerprint(resized); got == base {
+		t.Errorf("expected field type change to change fingerprint")
+	}
+
+	reordered := checkpoint.Clone()
+	reordered.Children[0], reordered.Children[1] = reordered.Children[1], reordered.Children[0]
+	if got := fingerprint(reordered); got == base {
+		t.Errorf("expected field order change to change fingerprint")
+	}
+
+	progressive := checkpoint.Clone()
+	progressive.Type = TypeProgressiveContainer
+	progressive.ActiveFields = []int{1, 1}
+	withGap := progressive.Clone()
+	withGap.ActiveFields = []int{1, 0, 1}
+	if fingerprint(progressive) == fingerprint(withGap) {
+		t.Errorf("expected active fields to change fingerprint")
+	}
+}
+
+func TestDefFingerprint_Errors(t *testing.T) {
+	refs := map[string]Def{
+		"Node": {Type: TypeContainer, Children: []Field{{Name: "next", Def: Def{Type: TypeRef, Ref: "Node"}}}},
+	}
+
+	node := refs["Node"]
+	if _, err := node.Fingerprint(refs); !errors.Is(err, ErrRecursiveType) {
+		t.Errorf("expected ErrRecursiveType, got: %v", err)
+	}
+
+	missing := Def{Type: TypeRef, Ref: "Missing"}
+	if _, err := missing.Fingerprint(refs); err == nil {
+		t.Error("expected error for missing ref, got nil")
+	}
+}
+
+func TestSchemaFingerprint(t *testing.T) {
+	parse := func(data string) Fingerprint {
+		t.Helper()
+		schema, err := ParseJSON([]byte(data))
+		if err != nil {
+			t.Fatalf("ParseJSON failed: %v", err)
+		}
+		f, err := schema.Fingerprint()
+		if err != nil {
+			t.Fatalf("Fingerprint failed: %v", err)
+		}
+		return f
+	}
+
+	base := parse(`{"defs": {"Root": {"type": "bytevector", "size": 32}}}`)
+	described := parse(`{"version": "2.0.0", "defs": {"Root": {"type": "bytevector", "size": 32, "description": "a root"}}}`)
+	if base != described {
+		t.Errorf("expected version and descriptions not to change schema fingerprint")
+	}
+
+	// Def names do not matter, only the structure of the defs
+	renamed := parse(`{"defs": {"Hash": {"type": "bytevector", "size": 32}}}`)
+	if base != renamed {
+		t.Errorf("expected def rename not to change schema fingerprint")
+	}
+	refs := parse(`{"defs": {"Root": {"type": "bytevector", "size": 32}, "A": {"type": "container", "children": [{"name": "r", "def": {"type": "ref", "ref": "Root"}}]}}}`)
+	renamedRefs := parse(`{"defs": {"Hash": {"type": "bytevector", "size": 32}, "B": {"type": "container", "children": [{"name": "r", "def": {"type": "ref", "ref": "Hash"}}]}}}`)
+	if refs != renamedRefs {
+		t.Errorf("expected renaming a def and its refs not to change schema fingerprint")
+	}
+
+	if base == parse(`{"defs": {"Root": {"type": "bytevector", "size": 20}}}`) {
+		t.Errorf("expected a size change to change schema fingerprint")
+	}
+	if base == refs {
+		t.Errorf("expected an added def to change schema fingerprint")
+	}
+	duplicated := parse(`{"defs": {"Root": {"type": "bytevector", "size": 32}, "Hash": {"type": "bytevector", "size": 32}}}`)
+	if base == duplicated {
+		t.Errorf("expected a second def of the same shape to change schema fingerprint")
+	}
+}
@@ -0,0 +1,396 @@
+package cuessz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The fuzz targets build a random schema and value from a seed, so
+//
+//	go test -fuzz FuzzSSZ_RoundTrip
+//
+// explores schemas as well as values. Without -fuzz only the seeds run
+
+func FuzzSSZ_RoundTrip(f *testing.F) {
+	for seed := int64(0); seed < 64; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewPCG(uint64(seed), 0))
+		schema, def := randomSchema(t, r)
+		value, err := schema.RandomValue(def, r, RandomOptions{EdgeBias: 0.2})
+		if err != nil {
+			t.Fatalf("RandomValue: %v", err)
+		}
+
+		data, err := schema.MarshalSSZ(def, value)
+		if err != nil {
+			t.Fatalf("MarshalSSZ: %v", err)
+		}
+		if size, fixed, err := schema.SizeSSZ(def); err != nil || fixed && size != uint64(len(data)) {
+			t.Fatalf("SizeSSZ = %d, %v, %v; encoded %d bytes", size, fixed, err, len(data))
+		}
+		if maxSize, err := schema.MaxSizeSSZ(def); err != nil || uint64(len(data)) > maxSize {
+			t.Fatalf("MaxSizeSSZ = %d, %v; encoded %d bytes", maxSize, err, len(data))
+		}
+
+		decoded, err := schema.UnmarshalSSZ(def, data)
+		if err != nil {
+			t.Fatalf("UnmarshalSSZ(%x): %v", data, err)
+		}
+		checkReencodes(t, schema, def, "UnmarshalSSZ", decoded, data)
+		viewed, err := schema.View(def, data).Decode()
+		if err != nil {
+			t.Fatalf("View.Decode: %v", err)
+		}
+		checkReencodes(t, schema, def, "View.Decode", viewed, data)
+		if err := walkStream(schema, def, data); err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+
+		root, err := schema.HashTreeRoot(def, value)
+		if err != nil {
+			t.Fatalf("HashTreeRoot: %v", err)
+		}
+		if decodedRoot, err := schema.HashTreeRoot(def, decoded); err != nil || decodedRoot != root {
+			t.Fatalf("HashTreeRoot of the decoded value = %s, %v; expected %s", decodedRoot, err, root)
+		}
+	})
+}
+
+// FuzzSSZ_Decode feeds arbitrary bytes to a random schema: the decoders must
+// agree on whether they are valid, and valid input must be canonical
+func FuzzSSZ_Decode(f *testing.F) {
+	for seed := int64(0); seed < 16; seed++ {
+		r := rand.New(rand.NewPCG(uint64(seed), 0))
+		schema, def := randomSchema(f, r)
+		value, err := schema.RandomValue(def, r, RandomOptions{})
+		if err != nil {
+			f.Fatalf("seed %d: RandomValue: %v", seed, err)
+		}
+		data, err := schema.MarshalSSZ(def, value)
+		if err != nil {
+			f.Fatalf("seed %d: MarshalSSZ: %v", seed, err)
+		}
+		f.Add(seed, data)
+		if len(data) > 0 {
+			f.Add(seed, data[:len(data)-1])
+			f.Add(seed, append(bytes.Clone(data), 0))
+		}
+	}
+	f.Fuzz(func(t *testing.T, seed int64, data []byte) {
+		schema, def := randomSchema(t, rand.New(rand.NewPCG(uint64(seed), 0)))
+
+		// Views do not validate, but no bytes may make them panic
+		walkView(schema.View(def, data))
+
+		value, err := schema.UnmarshalSSZ(def, data)
+		streamErr := walkStream(schema, def, data)
+		if (err == nil) != (streamErr == nil) {
+			t.Fatalf("UnmarshalSSZ(%x) = %v, but the stream decoder returned %v", data, err, streamErr)
+		}
+		if err != nil {
+			var de *DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("UnmarshalSSZ(%x): expected a *DecodeError, got: %v", data, err)
+			}
+			if de.Offset > uint64(len(data)) {
+				t.Fatalf("UnmarshalSSZ(%x): error at byte %d is past the end: %v", data, de.Offset, err)
+			}
+			return
+		}
+		checkReencodes(t, schema, def, "UnmarshalSSZ", value, data)
+	})
+}
+
+// referenceFixture is a case produced by, or for, a reference implementation.
+// Value is the value in the JSON form of MarshalValueJSON; a fixture without
+// one is invalid input that must fail to decode
+type referenceFixture struct {
+	Schema     json.RawMessage `json:"schema"`
+	Type       string          `json:"type"`
+	Serialized string          `json:"serialized"`
+	Root       string          `json:"root,omitempty"`
+	Value      json.RawMessage `json:"value,omitempty"`
+}
+
+// TestReferenceFixtures checks the *.json fixtures in testdata/reference, which
+// testdata/reference/ssz_reference.py serialized and hashed from cases written by
+// TestExportFixtures, and those in $CUESSZ_REFERENCE_FIXTURES when it is set
+func TestReferenceFixtures(t *testing.T) {
+	dirs := []string{filepath.Join("testdata", "reference")}
+	if dir := os.Getenv("CUESSZ_REFERENCE_FIXTURES"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	for _, dir := range dirs {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paths) == 0 {
+			t.Fatalf("no fixtures in %s", dir)
+		}
+		for _, path := range paths {
+			t.Run(path, func(t *testing.T) {
+				checkReferenceFixture(t, path)
+			})
+		}
+	}
+}
+
+func checkReferenceFixture(t *testing.T, path string) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture referenceFixture
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	schema, err := ParseJSON(fixture.Schema)
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(fixture.Serialized, "0x"))
+	if err != nil {
+		t.Fatalf("serialized: %v", err)
+	}
+
+	decoded, err := schema.UnmarshalSSZ(fixture.Type, data)
+	if fixture.Value == nil {
+		if err == nil {
+			t.Fatalf("UnmarshalSSZ(%s) succeeded on input the reference rejects", fixture.Serialized)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("UnmarshalSSZ: %v", err)
+	}
+	value, err := schema.UnmarshalValueJSON(fixture.Type, fixture.Value)
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+	checkReencodes(t, schema, fixture.Type, "fixture value", value, data)
+	checkReencodes(t, schema, fixture.Type, "UnmarshalSSZ", decoded, data)
+	root, err := schema.HashTreeRoot(fixture.Type, value)
+	if err != nil {
+		t.Fatalf("HashTreeRoot: %v", err)
+	}
+	if fixture.Root != "" && root.String() != fixture.Root {
+		t.Fatalf("HashTreeRoot = %s, the reference computes %s", root, fixture.Root)
+	}
+}
+
+// TestExportFixtures writes random cases to $CUESSZ_EXPORT_FIXTURES in the
+// fixture format, for a reference implementation to check or re-serialize
+func TestExportFixtures(t *testing.T) {
+	dir := os.Getenv("CUESSZ_EXPORT_FIXTURES")
+	if dir == "" {
+		t.Skip("CUESSZ_EXPORT_FIXTURES is not set")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(0); seed < 256; seed++ {
+		r := rand.New(rand.NewPCG(uint64(seed), 0))
+		schema, def := randomSchema(t, r)
+		value, err := schema.RandomValue(def, r, RandomOptions{EdgeBias: 0.2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fixture := referenceFixture{Type: def}
+		if fixture.Schema, err = json.Marshal(schema); err != nil {
+			t.Fatal(err)
+		}
+		if fixture.Value, err = schema.MarshalValueJSON(def, value); err != nil {
+			t.Fatal(err)
+		}
+		data, err := schema.MarshalSSZ(def, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixture.Serialized = "0x" + hex.EncodeToString(data)
+		root, err := schema.HashTreeRoot(def, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixture.Root = root.String()
+
+		out, err := json.MarshalIndent(fixture, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("random_%03d.json", seed)), append(out, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// checkReencodes checks that v encodes back to data
+func checkReencodes(t *testing.T, schema *Schema, def, what string, v Value, data []byte) {
+	t.Helper()
+	got, err := schema.MarshalSSZ(def, v)
+	if err != nil {
+		t.Fatalf("%s: MarshalSSZ: %v", what, err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("%s: re-encodes to %x, expected %x", what, got, data)
+	}
+}
+
+// walkStream reads every event of data with a stream decoder
+func walkStream(schema *Schema, def string, data []byte) error {
+	d, err := schema.NewStreamDecoder(def, bytes.NewReader(data), uint64(len(data)))
+	if err != nil {
+		return err
+	}
+	for {
+		if _, err := d.Next(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// walkView reads every field, element, bit and leaf under v, including the
+// bits and elements just out of range
+func walkView(v View) {
+	if v.Err() != nil {
+		return
+	}
+	d := v.Def()
+	switch d.Type {
+	case TypeContainer, TypeProgressiveContainer:
+		for _, child := range d.Children {
+			walkView(v.Field(child.Name))
+		}
+	case TypeVector, TypeList:
+		n, _ := v.Len()
+		for i := -1; i <= n; i++ {
+			walkView(v.Index(i))
+		}
+	case TypeBitVector, TypeBitList:
+		n, _ := v.Len()
+		for i := -1; i <= n; i++ {
+			_, _ = v.Bit(i)
+		}
+	case TypeUnion:
+		_, _ = v.Selector()
+		walkView(v.Option())
+	case TypeBoolean:
+		_, _ = v.Bool()
+	case TypeByteVector, TypeByteList:
+		_, _ = v.Bytes()
+	default:
+		_, _ = v.Uint64()
+		_, _ = v.BigInt()
+	}
+	_, _ = v.SSZ()
+}
+
+// randomSchema builds a valid schema of a few defs from r, each only referring
+// to the defs before it, and returns it with the name of the last def
+func randomSchema(t testing.TB, r *rand.Rand) (*Schema, string) {
+	t.Helper()
+	b := NewSchema()
+	var names []string
+	for i := range 1 + r.IntN(4) {
+		name := fmt.Sprintf("T%d", i)
+		b.Def(name, randomDef(r, names, 0))
+		names = append(names, name)
+	}
+	schema, err := b.Build()
+	if err != nil {
+		t.Fatalf("random schema is invalid: %v", err)
+	}
+	return schema, names[len(names)-1]
+}
+
+func randomDef(r *rand.Rand, refs []string, depth int) Def {
+	basic := []func() Def{Uint8, Uint16, Uint32, Uint64, Uint128, Uint256, Boolean}
+	kind := r.IntN(len(basic) + 8)
+	if depth >= 3 {
+		kind = r.IntN(len(basic))
+	}
+	switch kind - len(basic) {
+	case 0:
+		if len(refs) > 0 {
+			return Ref(refs[r.IntN(len(refs))])
+		}
+		return BitVector(randomSize(r))
+	case 1:
+		if r.IntN(2) == 0 {
+			return BitVector(randomSize(r))
+		}
+		return BitList(randomLimit(r))
+	case 2:
+		// The builders expand to vectors and lists of uint8, so also emit the
+		// shorthand that CollapseShorthand and struct literals produce
+		switch r.IntN(4) {
+		case 0:
+			return ByteVector(randomSize(r))
+		case 1:
+			return ByteList(randomLimit(r))
+		case 2:
+			return Def{Type: TypeByteVector, Size: randomSize(r)}
+		default:
+			return Def{Type: TypeByteList, Limit: randomLimit(r)}
+		}
+	case 3:
+		return Vector(randomDef(r, refs, depth+1), 1+uint64(r.IntN(4)))
+	case 4:
+		return List(randomDef(r, refs, depth+1), randomLimit(r))
+	case 5:
+		b := Container("")
+		for i := range 1 + r.IntN(4) {
+			b.Field(fmt.Sprintf("f%d", i), randomDef(r, refs, depth+1))
+		}
+		return b.Def()
+	case 6:
+		// One active bit per field, with inactive bits in between
+		n := 1 + r.IntN(4)
+		var active []int
+		for range n {
+			for r.IntN(3) == 0 {
+				active = append(active, 0)
+			}
+			active = append(active, 1)
+		}
+		b := ProgressiveContainer("", active...)
+		for i := range n {
+			b.Field(fmt.Sprintf("f%d", i), randomDef(r, refs, depth+1))
+		}
+		return b.Def()
+	case 7:
+		b := Union("")
+		for i := range 1 + r.IntN(3) {
+			b.Field(fmt.Sprintf("o%d", i), randomDef(r, refs, depth+1))
+		}
+		return b.Def()
+	}
+	return basic[kind]()
+}
+
+func randomSize(r *rand.Rand) uint64 {
+	return 1 + uint64(r.IntN(40))
+}
+
+// randomLimit is mostly small but sometimes large, which only changes the
+// merkle depth as lists are filled to at most 16 elements
+func randomLimit(r *rand.Rand) uint64 {
+	if r.IntN(8) == 0 {
+		return 1 << (10 + r.IntN(23))
+	}
+	return randomSize(r)
+}
@@ -2,7 +2,11 @@ module github.com/gfx-labs/cuessz
 
 go 1.24
 
-require cuelang.org/go v0.10.1
+require (
+	cuelang.org/go v0.10.1
+	github.com/golang/snappy v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	cuelabs.dev/go/oci/ociregistry v0.0.0-20240807094312-a32ad29eed79 // indirect
@@ -19,5 +23,4 @@ require (
 	golang.org/x/net v0.28.0 // indirect
 	golang.org/x/oauth2 v0.22.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,162 @@
+package cuessz
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// FromGoPackage derives a schema from the fastssz-tagged structs in the Go package in
+// dir, following the same rules as FromGoType
+// The named struct types become the schema's roots; with no names, every exported
+// struct type in the package is converted
+func FromGoPackage(dir string, names ...string) (*Schema, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one Go package in %s, found %d", dir, len(pkgs))
+	}
+
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		for _, name := range slices.Sorted(maps.Keys(pkg.Files)) {
+			files = append(files, pkg.Files[name])
+		}
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(dir, fset, files, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to type-check %s: %w", dir, err)
+	}
+
+	scope := pkg.Scope()
+	if len(names) == 0 {
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if ok && obj.Exported() {
+				if _, isStruct := obj.Type().Underlying().(*types.Struct); isStruct {
+					names = append(names, name)
+				}
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no exported struct types in %s", dir)
+		}
+	}
+
+	c := newGoConverter()
+	for _, name := range names {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type '%s' not found in %s", name, dir)
+		}
+		t := typesType{obj.Type()}
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("type '%s' is not a struct", name)
+		}
+		if _, err := c.addStruct(t); err != nil {
+			return nil, err
+		}
+		c.schema.Roots = append(c.schema.Roots, name)
+	}
+
+	if err := c.schema.Validate(); err != nil {
+		return nil, err
+	}
+	return c.schema, nil
+}
+
+// typesType adapts a go/types type to goType
+type typesType struct{ t types.Type }
+
+func (g typesType) Kind() reflect.Kind {
+	switch u := types.Unalias(g.t).Underlying().(type) {
+	case *types.Basic:
+		switch u.Kind() {
+		case types.Uint8:
+			return reflect.Uint8
+		case types.Uint16:
+			return reflect.Uint16
+		case types.Uint32:
+			return reflect.Uint32
+		case types.Uint64:
+			return reflect.Uint64
+		case types.Bool:
+			return reflect.Bool
+		}
+	case *types.Array:
+		return reflect.Array
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Pointer:
+		return reflect.Pointer
+	}
+	// Anything else is unsupported; the exact kind does not matter
+	return reflect.Invalid
+}
+
+func (g typesType) Name() string {
+	if named, ok := types.Unalias(g.t).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+func (g typesType) PkgPath() string {
+	if named, ok := types.Unalias(g.t).(*types.Named); ok && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Path()
+	}
+	return ""
+}
+
+func (g typesType) Elem() goType {
+	switch u := types.Unalias(g.t).Underlying().(type) {
+	case *types.Array:
+		return typesType{u.Elem()}
+	case *types.Slice:
+		return typesType{u.Elem()}
+	case *types.Pointer:
+		return typesType{u.Elem()}
+	}
+	panic(fmt.Sprintf("Elem of non-container type %s", g.t))
+}
+
+func (g typesType) Len() int {
+	return int(types.Unalias(g.t).Underlying().(*types.Array).Len())
+}
+
+func (g typesType) NumField() int {
+	return types.Unalias(g.t).Underlying().(*types.Struct).NumFields()
+}
+
+func (g typesType) Field(i int) goField {
+	s := types.Unalias(g.t).Underlying().(*types.Struct)
+	f := s.Field(i)
+	return goField{
+		Name:     f.Name(),
+		Tag:      reflect.StructTag(s.Tag(i)),
+		Type:     typesType{f.Type()},
+		Exported: f.Exported(),
+		Embedded: f.Embedded(),
+	}
+}
+
+func (g typesType) String() string {
+	return g.t.String()
+}
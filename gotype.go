@@ -0,0 +1,318 @@
+package cuessz
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FromGoType derives a schema from a fastssz-tagged Go struct
+// Every struct reachable from t becomes a container def named after its Go type and
+// t itself is the schema's root. Field names come from the json tag, or are the Go
+// name in snake_case. Slices need an ssz-size tag (vector) or ssz-max tag (list),
+// with comma separated entries per dimension and "?" for a dimension without one,
+// e.g. `ssz-size:"?,32" ssz-max:"16777216"` for a list of 32-byte roots
+// Bitfields are recognised by go-bitfield type names or an ssz:"bitlist" or
+// ssz:"bitvector" tag, and holiman/uint256 Ints become uint256
+func FromGoType(t reflect.Type) (*Schema, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("FromGoType: %s is not a struct", t)
+	}
+
+	c := newGoConverter()
+	name, err := c.addStruct(reflectType{t})
+	if err != nil {
+		return nil, err
+	}
+	c.schema.Roots = []string{name}
+
+	if err := c.schema.Validate(); err != nil {
+		return nil, err
+	}
+	return c.schema, nil
+}
+
+// goType abstracts over reflect.Type and go/types types, so the same conversion
+// works on structs loaded at runtime and on structs read from source
+type goType interface {
+	Kind() reflect.Kind
+	Name() string
+	PkgPath() string
+	Elem() goType
+	Len() int
+	NumField() int
+	Field(i int) goField
+	String() string
+}
+
+// goField is a struct field of a goType
+type goField struct {
+	Name     string
+	Tag      reflect.StructTag
+	Type     goType
+	Exported bool
+	Embedded bool
+}
+
+// reflectType adapts reflect.Type to goType
+type reflectType struct{ t reflect.Type }
+
+func (r reflectType) Kind() reflect.Kind { return r.t.Kind() }
+func (r reflectType) Name() string       { return r.t.Name() }
+func (r reflectType) PkgPath() string    { return r.t.PkgPath() }
+func (r reflectType) Elem() goType       { return reflectType{r.t.Elem()} }
+func (r reflectType) Len() int           { return r.t.Len() }
+func (r reflectType) NumField() int      { return r.t.NumField() }
+func (r reflectType) String() string     { return r.t.String() }
+
+func (r reflectType) Field(i int) goField {
+	f := r.t.Field(i)
+	return goField{Name: f.Name, Tag: f.Tag, Type: reflectType{f.Type}, Exported: f.IsExported(), Embedded: f.Anonymous}
+}
+
+// goConverter builds a schema from Go structs, one def per named struct
+type goConverter struct {
+	schema *Schema
+	// pkgs records the package each def came from, to catch two structs with the same name
+	pkgs map[string]string
+}
+
+func newGoConverter() *goConverter {
+	return &goConverter{
+		schema: &Schema{Version: defaultSchemaVersion, Defs: make(map[string]Def)},
+		pkgs:   make(map[string]string),
+	}
+}
+
+// addStruct adds a container def for a named struct and returns the def name
+func (c *goConverter) addStruct(t goType) (string, error) {
+	name := t.Name()
+	if name == "" {
+		return "", fmt.Errorf("anonymous struct %s cannot be a def, give it a name", t)
+	}
+	if pkg, ok := c.pkgs[name]; ok {
+		if pkg != t.PkgPath() {
+			return "", fmt.Errorf("def '%s' is defined in both %s and %s", name, pkg, t.PkgPath())
+		}
+		return name, nil
+	}
+	// Recorded before the fields are converted so recursive structs end in a ref;
+	// Validate reports the cycle
+	c.pkgs[name] = t.PkgPath()
+
+	def := Def{Type: TypeContainer}
+	if err := c.addFields(&def, t); err != nil {
+		return "", fmt.Errorf("def '%s' %w", name, err)
+	}
+	c.schema.Defs[name] = def
+	return name, nil
+}
+
+// addFields appends the SSZ fields of struct t to def, inlining embedded structs
+func (c *goConverter) addFields(def *Def, t goType) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Tag.Get("ssz") == "-" {
+			continue
+		}
+
+		// Embedded structs are inlined even when their type is unexported
+		if f.Embedded {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := c.addFields(def, ft); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !f.Exported {
+			continue
+		}
+
+		tags, err := parseSSZTags(f.Tag)
+		if err != nil {
+			return fmt.Errorf("at field '%s': %w", f.Name, err)
+		}
+		d, err := c.fieldDef(f.Type, tags, 0)
+		if err != nil {
+			return fmt.Errorf("at field '%s': %w", f.Name, err)
+		}
+		def.Children = append(def.Children, Field{Name: goFieldName(f), Def: d})
+	}
+	return nil
+}
+
+// bitvectorName matches go-bitfield's fixed size bitvector types, e.g. Bitvector512
+var bitvectorName = regexp.MustCompile(`^Bitvector([0-9]+)$`)
+
+// fieldDef converts the type at dimension dim of a field
+func (c *goConverter) fieldDef(t goType, tags sszTags, dim int) (Def, error) {
+	isBitfieldPkg := strings.HasSuffix(t.PkgPath(), "go-bitfield")
+
+	switch {
+	case t.PkgPath() == "github.com/holiman/uint256" && t.Name() == "Int":
+		return Uint256(), nil
+	case dim == 0 && (tags.kind == "uint256" || tags.kind == "uint128"):
+		if tags.kind == "uint128" {
+			return Uint128(), nil
+		}
+		return Uint256(), nil
+	case dim == 0 && (tags.kind == "bitlist" || isBitfieldPkg && t.Name() == "Bitlist"):
+		limit := tags.max(dim)
+		if limit == 0 {
+			return Def{}, fmt.Errorf("bitlist needs an ssz-max tag")
+		}
+		return BitList(limit), nil
+	case isBitfieldPkg && bitvectorName.MatchString(t.Name()):
+		bits, _ := strconv.ParseUint(bitvectorName.FindStringSubmatch(t.Name())[1], 10, 64)
+		return BitVector(bits), nil
+	case dim == 0 && tags.kind == "bitvector":
+		// Like fastssz, the ssz-size of a bitvector counts bytes
+		size := tags.size(dim)
+		if t.Kind() == reflect.Array {
+			size = uint64(t.Len())
+		}
+		if size == 0 {
+			return Def{}, fmt.Errorf("bitvector needs an ssz-size tag")
+		}
+		return BitVector(size * 8), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Uint8:
+		return Uint8(), nil
+	case reflect.Uint16:
+		return Uint16(), nil
+	case reflect.Uint32:
+		return Uint32(), nil
+	case reflect.Uint64:
+		return Uint64(), nil
+	case reflect.Bool:
+		return Boolean(), nil
+	case reflect.Struct:
+		name, err := c.addStruct(t)
+		if err != nil {
+			return Def{}, err
+		}
+		return Ref(name), nil
+	case reflect.Pointer:
+		if t.Elem().Kind() != reflect.Struct {
+			return Def{}, fmt.Errorf("unsupported Go type %s: only pointers to structs are allowed", t)
+		}
+		return c.fieldDef(t.Elem(), tags, dim)
+	case reflect.Array:
+		n := uint64(t.Len())
+		if size := tags.size(dim); size != 0 && size != n {
+			return Def{}, fmt.Errorf("ssz-size %d does not match array length %d", size, n)
+		}
+		elem, err := c.fieldDef(t.Elem(), tags, dim+1)
+		if err != nil {
+			return Def{}, err
+		}
+		return Vector(elem, n), nil
+	case reflect.Slice:
+		elem, err := c.fieldDef(t.Elem(), tags, dim+1)
+		if err != nil {
+			return Def{}, err
+		}
+		if size := tags.size(dim); size != 0 {
+			return Vector(elem, size), nil
+		}
+		if limit := tags.max(dim); limit != 0 {
+			return List(elem, limit), nil
+		}
+		return Def{}, fmt.Errorf("slice %s needs an ssz-size or ssz-max tag", t)
+	default:
+		return Def{}, fmt.Errorf("unsupported Go type %s", t)
+	}
+}
+
+// sszTags holds the fastssz struct tags of a field, one size and max per dimension
+type sszTags struct {
+	sizes []uint64
+	maxes []uint64
+	kind  string
+}
+
+// parseSSZTags parses the ssz, ssz-size and ssz-max tags; "?" entries become 0
+func parseSSZTags(tag reflect.StructTag) (sszTags, error) {
+	tags := sszTags{kind: tag.Get("ssz")}
+	var err error
+	if tags.sizes, err = parseTagDims(tag.Get("ssz-size")); err != nil {
+		return sszTags{}, fmt.Errorf("invalid ssz-size tag: %w", err)
+	}
+	if tags.maxes, err = parseTagDims(tag.Get("ssz-max")); err != nil {
+		return sszTags{}, fmt.Errorf("invalid ssz-max tag: %w", err)
+	}
+	return tags, nil
+}
+
+func parseTagDims(value string) ([]uint64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.Split(value, ",")
+	dims := make([]uint64, len(parts))
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "?" {
+			continue
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		dims[i] = n
+	}
+	return dims, nil
+}
+
+func (t sszTags) size(dim int) uint64 {
+	if dim < len(t.sizes) {
+		return t.sizes[dim]
+	}
+	return 0
+}
+
+func (t sszTags) max(dim int) uint64 {
+	if dim < len(t.maxes) {
+		return t.maxes[dim]
+	}
+	return 0
+}
+
+// goFieldName is the schema name of a struct field: its json name, or its Go name in snake_case
+func goFieldName(f goField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return snakeCase(f.Name)
+}
+
+// snakeCase converts a Go identifier to snake_case, keeping acronyms together:
+// ParentRoot -> parent_root, BLSToExecutionChanges -> bls_to_execution_changes
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package cuessz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type goCheckpoint struct {
+	Epoch uint64   `json:"epoch"`
+	Root  [32]byte `json:"root"`
+}
+
+type goPendingAttestation struct {
+	AggregationBits []byte `ssz:"bitlist" ssz-max:"2048"`
+	InclusionDelay  uint64
+	ProposerIndex   uint64
+	Checkpoints     []*goCheckpoint `ssz-max:"4"`
+	Internal        string          `ssz:"-"`
+	unexported      uint64
+}
+
+type goState struct {
+	goEmbedded
+	HistoricalRoots   [][]byte `ssz-size:"?,32" ssz-max:"16777216"`
+	JustificationBits []byte   `ssz:"bitvector" ssz-size:"1"`
+	Balances          [4]uint64
+	Slashed           bool
+	Pending           *goPendingAttestation
+}
+
+type goEmbedded struct {
+	GenesisTime uint64
+}
+
+func TestFromGoType(t *testing.T) {
+	schema, err := FromGoType(reflect.TypeOf(&goState{}))
+	if err != nil {
+		t.Fatalf("FromGoType failed: %v", err)
+	}
+
+	want := NewSchema().
+		Add(
+			Container("goCheckpoint").
+				Field("epoch", Uint64()).
+				Field("root", ByteVector(32)),
+			Container("goPendingAttestation").
+				Field("aggregation_bits", BitList(2048)).
+				Field("inclusion_delay", Uint64()).
+				Field("proposer_index", Uint64()).
+				Field("checkpoints", List(Ref("goCheckpoint"), 4)),
+			Container("goState").
+				Field("genesis_time", Uint64()).
+				Field("historical_roots", List(ByteVector(32), 16777216)).
+				Field("justification_bits", BitVector(8)).
+				Field("balances", Vector(Uint64(), 4)).
+				Field("slashed", Boolean()).
+				Field("pending", Ref("goPendingAttestation")),
+		).
+		Roots("goState").
+		MustBuild()
+
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("unexpected schema:\n%s\nwant:\n%s", mustCanonical(t, schema), mustCanonical(t, want))
+	}
+}
+
+func TestFromGoType_Errors(t *testing.T) {
+	type noTag struct {
+		Roots [][32]byte
+	}
+	type badInt struct {
+		Count int
+	}
+	type badTag struct {
+		Data []byte `ssz-size:"lots"`
+	}
+	type bitlistNoMax struct {
+		Bits []byte `ssz:"bitlist"`
+	}
+	type sizeMismatch struct {
+		Root [32]byte `ssz-size:"48"`
+	}
+
+	tests := []struct {
+		typ  reflect.Type
+		want string
+	}{
+		{reflect.TypeOf(noTag{}), "needs an ssz-size or ssz-max tag"},
+		{reflect.TypeOf(badInt{}), "unsupported Go type int"},
+		{reflect.TypeOf(badTag{}), "invalid ssz-size tag"},
+		{reflect.TypeOf(bitlistNoMax{}), "bitlist needs an ssz-max tag"},
+		{reflect.TypeOf(sizeMismatch{}), "does not match array length 32"},
+		{reflect.TypeOf(uint64(0)), "is not a struct"},
+	}
+	for _, tt := range tests {
+		_, err := FromGoType(tt.typ)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got: %v", tt.typ, tt.want, err)
+		}
+	}
+}
+
+func TestFromGoPackage(t *testing.T) {
+	schema, err := FromGoPackage("testdata/gostructs")
+	if err != nil {
+		t.Fatalf("FromGoPackage failed: %v", err)
+	}
+
+	wantRoots := []string{"Attestation", "AttestationData", "Checkpoint", "HistoricalBatch", "SyncAggregate"}
+	if !reflect.DeepEqual(schema.Roots, wantRoots) {
+		t.Errorf("expected roots %v, got %v", wantRoots, schema.Roots)
+	}
+
+	want := NewSchema().
+		Add(
+			Container("Checkpoint").
+				Field("epoch", Uint64()).
+				Field("root", ByteVector(32)),
+			Container("AttestationData").
+				Field("slot", Uint64()).
+				Field("index", Uint64()).
+				Field("beacon_block_root", ByteVector(32)).
+				Field("source", Ref("Checkpoint")).
+				Field("target", Ref("Checkpoint")),
+			Container("Attestation").
+				Field("aggregation_bits", BitList(2048)).
+				Field("data", Ref("AttestationData")).
+				Field("signature", ByteVector(96)),
+			Container("HistoricalBatch").
+				Field("block_roots", Vector(ByteVector(32), 8192)).
+				Field("state_roots", Vector(ByteVector(32), 8192)),
+			Container("SyncAggregate").
+				Field("sync_committee_bits", BitVector(512)).
+				Field("sync_committee_signature", ByteVector(96)),
+		).
+		Roots(wantRoots...).
+		MustBuild()
+
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("unexpected schema:\n%s\nwant:\n%s", mustCanonical(t, schema), mustCanonical(t, want))
+	}
+
+	only, err := FromGoPackage("testdata/gostructs", "Attestation")
+	if err != nil {
+		t.Fatalf("FromGoPackage failed: %v", err)
+	}
+	if len(only.Defs) != 3 {
+		t.Errorf("expected Attestation and its 2 dependencies, got %d defs", len(only.Defs))
+	}
+
+	if _, err := FromGoPackage("testdata/gostructs", "Alias"); err == nil {
+		t.Error("expected error for non-struct type, got nil")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"ParentRoot":            "parent_root",
+		"BLSToExecutionChanges": "bls_to_execution_changes",
+		"Eth1DepositIndex":      "eth1_deposit_index",
+		"ID":                    "id",
+		"Slot":                  "slot",
+	} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
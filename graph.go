@@ -0,0 +1,171 @@
+package cuessz
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Graph is the def reference graph of a schema
+type Graph struct {
+	Nodes []string    `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphEdge is a reference from one def to another
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Field is the field path in From holding the ref, e.g. "body.attestations[]",
+	// empty when From is itself a ref
+	Field string `json:"field,omitempty"`
+}
+
+// GraphJSON parses and validates a JSON schema like ParseJSON and returns its def
+// reference graph, with nodes sorted by name and edges in field order
+func GraphJSON(data []byte) (*Graph, error) {
+	_, unified, err := parseJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	refGraph, err := collectRefGraphFromCUE(unified)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{Nodes: slices.Sorted(maps.Keys(refGraph))}
+	for _, name := range g.Nodes {
+		for _, refLoc := range refGraph[name] {
+			g.Edges = append(g.Edges, GraphEdge{From: name, To: refLoc.ref, Field: joinFieldPath(refLoc.fields)})
+		}
+	}
+	return g, nil
+}
+
+// joinFieldPath joins field names with dots, attaching "[]" element markers directly
+func joinFieldPath(fields []string) string {
+	var sb strings.Builder
+	for _, field := range fields {
+		if field != "[]" && sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(field)
+	}
+	return sb.String()
+}
+
+// Subgraph returns the part of the graph reachable from root
+func (g *Graph) Subgraph(root string) (*Graph, error) {
+	if !slices.Contains(g.Nodes, root) {
+		return nil, fmt.Errorf("def '%s' is not defined in schema defs", root)
+	}
+
+	outgoing := make(map[string][]GraphEdge)
+	for _, edge := range g.Edges {
+		outgoing[edge.From] = append(outgoing[edge.From], edge)
+	}
+
+	reached := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, edge := range outgoing[name] {
+			if !reached[edge.To] {
+				reached[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	sub := &Graph{}
+	for _, name := range g.Nodes {
+		if reached[name] {
+			sub.Nodes = append(sub.Nodes, name)
+		}
+	}
+	for _, edge := range g.Edges {
+		if reached[edge.From] {
+			sub.Edges = append(sub.Edges, edge)
+		}
+	}
+	return sub, nil
+}
+
+// WithoutFields returns the graph with field labels dropped and parallel edges
+// between the same pair of defs merged
+func (g *Graph) WithoutFields() *Graph {
+	out := &Graph{Nodes: g.Nodes}
+	seen := make(map[GraphEdge]bool)
+	for _, edge := range g.Edges {
+		edge.Field = ""
+		if !seen[edge] {
+			seen[edge] = true
+			out.Edges = append(out.Edges, edge)
+		}
+	}
+	return out
+}
+
+// WriteDOT writes the graph in Graphviz DOT format
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph schema {\n")
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=box];\n")
+	for _, name := range g.Nodes {
+		fmt.Fprintf(&sb, "\t%q;\n", name)
+	}
+	for _, edge := range g.Edges {
+		if edge.Field != "" {
+			fmt.Fprintf(&sb, "\t%q -> %q [label=%q];\n", edge.From, edge.To, edge.Field)
+		} else {
+			fmt.Fprintf(&sb, "\t%q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	for _, name := range g.Nodes {
+		fmt.Fprintf(&sb, "\t%s[\"%s\"]\n", mermaidID(name), name)
+	}
+	for _, edge := range g.Edges {
+		if edge.Field != "" {
+			fmt.Fprintf(&sb, "\t%s -->|\"%s\"| %s\n", mermaidID(edge.From), edge.Field, mermaidID(edge.To))
+		} else {
+			fmt.Fprintf(&sb, "\t%s --> %s\n", mermaidID(edge.From), mermaidID(edge.To))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mermaidID turns a def name into a Mermaid node id, which may only contain
+// letters, digits and underscores. Underscores are doubled and other bytes
+// written as _ and two hex digits, so distinct names never share an id
+func mermaidID(name string) string {
+	var sb strings.Builder
+	for _, b := range []byte(name) {
+		switch {
+		case ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9'):
+			sb.WriteByte(b)
+		case b == '_':
+			sb.WriteString("__")
+		default:
+			fmt.Fprintf(&sb, "_%02x", b)
+		}
+	}
+	return sb.String()
+}
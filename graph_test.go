@@ -0,0 +1,124 @@
+package cuessz
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var graphSchema = []byte(`{
+	"version": "1.0.0",
+	"defs": {
+		"Root": {"type": "bytevector", "size": 32},
+		"Checkpoint": {
+			"type": "container",
+			"children": [
+				{"name": "epoch", "def": {"type": "uint64"}},
+				{"name": "root", "def": {"type": "ref", "ref": "Root"}}
+			]
+		},
+		"State": {
+			"type": "container",
+			"children": [
+				{"name": "block_roots", "def": {
+					"type": "vector",
+					"size": 8,
+					"children": [{"name": "element", "def": {"type": "ref", "ref": "Root"}}]
+				}},
+				{"name": "finalized_checkpoint", "def": {"type": "ref", "ref": "Checkpoint"}},
+				{"name": "justified_checkpoint", "def": {"type": "ref", "ref": "Checkpoint"}}
+			]
+		},
+		"Unrelated": {"type": "uint64"}
+	}
+}`)
+
+func TestGraphJSON(t *testing.T) {
+	g, err := GraphJSON(graphSchema)
+	if err != nil {
+		t.Fatalf("GraphJSON failed: %v", err)
+	}
+
+	wantNodes := []string{"Checkpoint", "Root", "State", "Unrelated"}
+	if !reflect.DeepEqual(g.Nodes, wantNodes) {
+		t.Errorf("expected nodes %v, got %v", wantNodes, g.Nodes)
+	}
+
+	wantEdges := []GraphEdge{
+		{From: "Checkpoint", To: "Root", Field: "root"},
+		{From: "State", To: "Root", Field: "block_roots[]"},
+		{From: "State", To: "Checkpoint", Field: "finalized_checkpoint"},
+		{From: "State", To: "Checkpoint", Field: "justified_checkpoint"},
+	}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Errorf("expected edges %v, got %v", wantEdges, g.Edges)
+	}
+}
+
+func TestGraph_SubgraphAndWithoutFields(t *testing.T) {
+	g, err := GraphJSON(graphSchema)
+	if err != nil {
+		t.Fatalf("GraphJSON failed: %v", err)
+	}
+
+	sub, err := g.Subgraph("Checkpoint")
+	if err != nil {
+		t.Fatalf("Subgraph failed: %v", err)
+	}
+	if !reflect.DeepEqual(sub.Nodes, []string{"Checkpoint", "Root"}) {
+		t.Errorf("expected Checkpoint subgraph nodes, got %v", sub.Nodes)
+	}
+	if len(sub.Edges) != 1 {
+		t.Errorf("expected 1 edge in Checkpoint subgraph, got %v", sub.Edges)
+	}
+
+	if _, err := g.Subgraph("Missing"); err == nil {
+		t.Error("expected error for unknown root, got nil")
+	}
+
+	merged := g.WithoutFields()
+	if len(merged.Edges) != 3 {
+		t.Errorf("expected parallel edges to be merged into 3, got %v", merged.Edges)
+	}
+}
+
+func TestGraph_Writers(t *testing.T) {
+	g, err := GraphJSON(graphSchema)
+	if err != nil {
+		t.Fatalf("GraphJSON failed: %v", err)
+	}
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	if !strings.Contains(dot.String(), `"State" -> "Root" [label="block_roots[]"];`) {
+		t.Errorf("unexpected DOT output:\n%s", dot.String())
+	}
+
+	var mermaid bytes.Buffer
+	if err := g.WithoutFields().WriteMermaid(&mermaid); err != nil {
+		t.Fatalf("WriteMermaid failed: %v", err)
+	}
+	if !strings.HasPrefix(mermaid.String(), "graph LR\n") || !strings.Contains(mermaid.String(), "\tState --> Checkpoint\n") {
+		t.Errorf("unexpected Mermaid output:\n%s", mermaid.String())
+	}
+}
+
+func TestMermaidID(t *testing.T) {
+	seen := map[string]string{}
+	for _, name := range []string{"State", "A-B", "A.B", "A_B", "A__B", "A_2dB", "Ä"} {
+		id := mermaidID(name)
+		if strings.Trim(id, "_abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") != "" {
+			t.Errorf("mermaidID(%q) = %q has characters Mermaid ids cannot", name, id)
+		}
+		if other, ok := seen[id]; ok {
+			t.Errorf("mermaidID(%q) = mermaidID(%q) = %q", name, other, id)
+		}
+		seen[id] = name
+	}
+	if got := mermaidID("State"); got != "State" {
+		t.Errorf("mermaidID(State) = %q, want it unchanged", got)
+	}
+}
@@ -0,0 +1,182 @@
+package cuessz
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// jsonSchemaDialect is the JSON Schema draft JSONSchema documents declare
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema document describing the canonical JSON form of
+// values of every def, as written by MarshalValueJSON:
+//   - uints are decimal strings, as in the beacon-API
+//   - byte vectors and lists, bitvectors and bitlists are 0x-prefixed hex strings
+//   - vectors and lists are arrays, containers are objects keyed by field name
+//   - unions are {"selector": <n>, "value": <value>} objects
+//
+// Each def is under $defs; with a root the document itself validates that def
+func (s *Schema) JSONSchema(root string) (map[string]any, error) {
+	defs := make(map[string]any, len(s.Defs))
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		js, err := defJSONSchema(&def)
+		if err != nil {
+			return nil, fmt.Errorf("def '%s': %w", name, err)
+		}
+		defs[name] = js
+	}
+
+	doc := map[string]any{
+		"$schema": jsonSchemaDialect,
+		"$defs":   defs,
+	}
+	if s.Metadata != nil && s.Metadata.Description != "" {
+		doc["description"] = s.Metadata.Description
+	}
+	if root != "" {
+		if _, ok := s.Defs[root]; !ok {
+			return nil, fmt.Errorf("root '%s' is not defined in schema defs", root)
+		}
+		doc["$ref"] = jsonSchemaRef(root)
+	}
+	return doc, nil
+}
+
+// defJSONSchema returns the JSON Schema of a single def
+func defJSONSchema(d *Def) (map[string]any, error) {
+	js, err := defJSONSchemaType(d)
+	if err != nil {
+		return nil, err
+	}
+	if d.Description != nil {
+		js["description"] = *d.Description
+	}
+	return js, nil
+}
+
+func defJSONSchemaType(d *Def) (map[string]any, error) {
+	switch d.Type {
+	case TypeUint8:
+		return jsonSchemaDecimal(3), nil
+	case TypeUint16:
+		return jsonSchemaDecimal(5), nil
+	case TypeUint32:
+		return jsonSchemaDecimal(10), nil
+	case TypeUint64:
+		return jsonSchemaDecimal(20), nil
+	case TypeUint128:
+		return jsonSchemaDecimal(39), nil
+	case TypeUint256:
+		return jsonSchemaDecimal(78), nil
+	case TypeBoolean:
+		return map[string]any{"type": "boolean"}, nil
+	case TypeBitVector:
+		return jsonSchemaHex((d.Size+7)/8, true), nil
+	case TypeBitList:
+		// The delimiter bit means a full bitlist takes one byte more
+		return jsonSchemaHex(d.Limit/8+1, false), nil
+	case TypeByteVector:
+		return jsonSchemaHex(d.Size, true), nil
+	case TypeByteList:
+		return jsonSchemaHex(d.Limit, false), nil
+	case TypeVector, TypeList:
+		if d.IsBytes() {
+			if d.Type == TypeVector {
+				return jsonSchemaHex(d.Size, true), nil
+			}
+			return jsonSchemaHex(d.Limit, false), nil
+		}
+		if len(d.Children) != 1 {
+			return nil, fmt.Errorf("%s must have exactly one child", d.Type)
+		}
+		items, err := defJSONSchema(&d.Children[0].Def)
+		if err != nil {
+			return nil, err
+		}
+		js := map[string]any{"type": "array", "items": items}
+		if d.Type == TypeVector {
+			js["minItems"] = d.Size
+			js["maxItems"] = d.Size
+		} else {
+			js["maxItems"] = d.Limit
+		}
+		return js, nil
+	case TypeContainer, TypeProgressiveContainer:
+		properties := make(map[string]any, len(d.Children))
+		required := make([]string, 0, len(d.Children))
+		for i := range d.Children {
+			child := &d.Children[i]
+			js, err := defJSONSchema(&child.Def)
+			if err != nil {
+				return nil, fmt.Errorf("field '%s': %w", child.Name, err)
+			}
+			if child.Description != nil {
+				js["description"] = *child.Description
+			}
+			properties[child.Name] = js
+			required = append(required, child.Name)
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}, nil
+	case TypeUnion:
+		options := make([]any, 0, len(d.Children))
+		for i := range d.Children {
+			child := &d.Children[i]
+			value, err := defJSONSchema(&child.Def)
+			if err != nil {
+				return nil, fmt.Errorf("option '%s': %w", child.Name, err)
+			}
+			options = append(options, map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"selector": map[string]any{"const": i},
+					"value":    value,
+				},
+				"required":             []string{"selector", "value"},
+				"additionalProperties": false,
+			})
+		}
+		return map[string]any{"oneOf": options}, nil
+	case TypeRef:
+		return map[string]any{"$ref": jsonSchemaRef(d.Ref)}, nil
+	default:
+		return nil, fmt.Errorf("invalid type '%s'", d.Type)
+	}
+}
+
+func jsonSchemaRef(name string) string {
+	return "#/$defs/" + name
+}
+
+// jsonSchemaDecimal is a decimal string of at most maxDigits digits, the
+// beacon-API form of every uint
+func jsonSchemaDecimal(maxDigits int) map[string]any {
+	return map[string]any{
+		"type":      "string",
+		"pattern":   "^(0|[1-9][0-9]*)$",
+		"maxLength": maxDigits,
+	}
+}
+
+// jsonSchemaHex is a 0x-prefixed hex string of exactly (or, unless exact, at most) n bytes
+// Lengths are checked with minLength/maxLength since a regex repetition count
+// of 2^32 bytes is beyond many validators
+func jsonSchemaHex(n uint64, exact bool) map[string]any {
+	// Limits go up to 2^64-1, so the length saturates rather than wrapping
+	length := addSaturating(2, mulSaturating(2, n))
+	js := map[string]any{
+		"type":      "string",
+		"pattern":   "^0x([0-9a-fA-F]{2})*$",
+		"maxLength": length,
+	}
+	if exact {
+		js["minLength"] = length
+	}
+	return js
+}
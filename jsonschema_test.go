@@ -0,0 +1,122 @@
+package cuessz
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := NewSchema().
+		Def("Root", ByteVector(32)).
+		Add(
+			Container("Checkpoint").
+				Field("epoch", Uint64()).
+				Field("root", Ref("Root")),
+			Container("Mixed").
+				Field("flags", Uint8().WithDescription("participation flags")).
+				Field("bits", BitList(2048)).
+				Field("justification", BitVector(4)).
+				Field("extra_data", ByteList(32)).
+				Field("balance", Uint256()).
+				Field("checkpoints", List(Ref("Checkpoint"), 4)).
+				Field("roots", Vector(Ref("Root"), 2)).
+				Field("ok", Boolean()),
+		).
+		MustBuild()
+	schema.Defs["Choice"] = Def{Type: TypeUnion, Children: []Field{
+		{Name: "a", Def: Uint16()},
+		{Name: "b", Def: Ref("Checkpoint")},
+	}}
+
+	doc, err := schema.JSONSchema("Mixed")
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+
+	// Compare through JSON so number types do not matter
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	var want map[string]any
+	err = json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/Mixed",
+		"$defs": {
+			"Root": {"type": "string", "pattern": "^0x([0-9a-fA-F]{2})*$", "minLength": 66, "maxLength": 66},
+			"Checkpoint": {
+				"type": "object",
+				"properties": {
+					"epoch": {"type": "string", "pattern": "^(0|[1-9][0-9]*)$", "maxLength": 20},
+					"root": {"$ref": "#/$defs/Root"}
+				},
+				"required": ["epoch", "root"],
+				"additionalProperties": false
+			},
+			"Choice": {"oneOf": [
+				{
+					"type": "object",
+					"properties": {
+						"selector": {"const": 0},
+						"value": {"type": "string", "pattern": "^(0|[1-9][0-9]*)$", "maxLength": 5}
+					},
+					"required": ["selector", "value"],
+					"additionalProperties": false
+				},
+				{
+					"type": "object",
+					"properties": {
+						"selector": {"const": 1},
+						"value": {"$ref": "#/$defs/Checkpoint"}
+					},
+					"required": ["selector", "value"],
+					"additionalProperties": false
+				}
+			]},
+			"Mixed": {
+				"type": "object",
+				"properties": {
+					"flags": {"type": "string", "pattern": "^(0|[1-9][0-9]*)$", "maxLength": 3, "description": "participation flags"},
+					"bits": {"type": "string", "pattern": "^0x([0-9a-fA-F]{2})*$", "maxLength": 516},
+					"justification": {"type": "string", "pattern": "^0x([0-9a-fA-F]{2})*$", "minLength": 4, "maxLength": 4},
+					"extra_data": {"type": "string", "pattern": "^0x([0-9a-fA-F]{2})*$", "maxLength": 66},
+					"balance": {"type": "string", "pattern": "^(0|[1-9][0-9]*)$", "maxLength": 78},
+					"checkpoints": {"type": "array", "items": {"$ref": "#/$defs/Checkpoint"}, "maxItems": 4},
+					"roots": {"type": "array", "items": {"$ref": "#/$defs/Root"}, "minItems": 2, "maxItems": 2},
+					"ok": {"type": "boolean"}
+				},
+				"required": ["flags", "bits", "justification", "extra_data", "balance", "checkpoints", "roots", "ok"],
+				"additionalProperties": false
+			}
+		}
+	}`), &want)
+	if err != nil {
+		t.Fatalf("bad test JSON: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected JSON Schema:\n%s", data)
+	}
+
+	if _, err := schema.JSONSchema("Missing"); err == nil {
+		t.Error("expected error for unknown root, got nil")
+	}
+}
+
+func TestJSONSchema_HugeLimit(t *testing.T) {
+	schema := NewSchema().Def("Huge", ByteList(math.MaxUint64)).MustBuild()
+	js, err := schema.JSONSchema("Huge")
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	if got := js["$defs"].(map[string]any)["Huge"].(map[string]any)["maxLength"]; got != uint64(math.MaxUint64) {
+		t.Errorf("expected maxLength to saturate, got %v", got)
+	}
+}
@@ -0,0 +1,283 @@
+package cuessz
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is the level at which a lint rule reports issues
+type Severity int
+
+const (
+	SeverityOff Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityOff:
+		return "off"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// ParseSeverity parses a severity name as used in .cuessz.yaml
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(name) {
+	case "off":
+		return SeverityOff, nil
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	default:
+		return SeverityOff, fmt.Errorf("unknown severity '%s' - must be one of: off, info, warning, error", name)
+	}
+}
+
+// Issue is a single finding reported by a lint rule
+type Issue struct {
+	Rule     string
+	Severity Severity
+	Def      string // The def the issue was found in
+	Path     string // Dotted field path within the def, empty for the def itself
+	Message  string
+}
+
+func (i Issue) String() string {
+	location := fmt.Sprintf("def '%s'", i.Def)
+	if i.Path != "" {
+		location += fmt.Sprintf(" field '%s'", i.Path)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", i.Severity, location, i.Message, i.Rule)
+}
+
+// Linter is a lint rule over a schema
+// Rules only fill in Def, Path and Message of the issues they return; Lint sets
+// Rule and Severity from the configuration
+type Linter interface {
+	// Name is the rule name used in .cuessz.yaml and in nolint directives
+	Name() string
+
+	// DefaultSeverity is used when the configuration does not mention the rule
+	DefaultSeverity() Severity
+
+	// Configure applies rule-specific options from the configuration
+	Configure(options map[string]any) error
+
+	// Lint returns the issues found in the schema
+	Lint(s *Schema) []Issue
+}
+
+// Config is the contents of a .cuessz.yaml file
+type Config struct {
+	Lint LintConfig `yaml:"lint"`
+}
+
+// LintConfig configures lint rules by name
+type LintConfig struct {
+	Rules map[string]RuleConfig `yaml:"rules"`
+}
+
+// RuleConfig sets the severity and options of a single rule
+// In YAML it is either a bare severity (`field-naming: error`) or a mapping
+// with `severity` and `options` keys
+type RuleConfig struct {
+	Severity *Severity
+	Options  map[string]any
+}
+
+// UnmarshalYAML accepts both the bare severity and the mapping form
+func (c *RuleConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		sev, err := ParseSeverity(node.Value)
+		if err != nil {
+			return err
+		}
+		c.Severity = &sev
+		return nil
+	}
+
+	var raw struct {
+		Severity string         `yaml:"severity"`
+		Options  map[string]any `yaml:"options"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	if raw.Severity != "" {
+		sev, err := ParseSeverity(raw.Severity)
+		if err != nil {
+			return err
+		}
+		c.Severity = &sev
+	}
+	c.Options = raw.Options
+	return nil
+}
+
+// ParseConfig parses the contents of a .cuessz.yaml file
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// LoadConfig reads and parses a .cuessz.yaml file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// Lint runs the linters over the schema with the given configuration
+// Issues suppressed by a nolint directive in a description are dropped, and the
+// rest are returned sorted by def, path and rule
+func Lint(s *Schema, linters []Linter, cfg LintConfig) ([]Issue, error) {
+	known := make(map[string]bool, len(linters))
+	for _, l := range linters {
+		known[l.Name()] = true
+	}
+	for name := range cfg.Rules {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule '%s' in config", name)
+		}
+	}
+
+	suppressions := collectSuppressions(s)
+
+	var issues []Issue
+	for _, l := range linters {
+		severity := l.DefaultSeverity()
+		rule, configured := cfg.Rules[l.Name()]
+		if configured && rule.Severity != nil {
+			severity = *rule.Severity
+		}
+		if severity == SeverityOff {
+			continue
+		}
+		if err := l.Configure(rule.Options); err != nil {
+			return nil, fmt.Errorf("lint rule '%s': %w", l.Name(), err)
+		}
+
+		for _, issue := range l.Lint(s) {
+			issue.Rule = l.Name()
+			issue.Severity = severity
+			if suppressions.suppressed(issue) {
+				continue
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	slices.SortStableFunc(issues, func(a, b Issue) int {
+		return cmp.Or(
+			strings.Compare(a.Def, b.Def),
+			strings.Compare(a.Path, b.Path),
+			strings.Compare(a.Rule, b.Rule),
+		)
+	})
+	return issues, nil
+}
+
+// nolintDirective marks a description as suppressing lint rules, e.g.
+// "Legacy type. nolint:deprecated-type,missing-description" or a bare "nolint"
+const nolintDirective = "nolint"
+
+// suppressions maps "Def" or "Def.field.path" to the rules suppressed there;
+// an empty rule set suppresses every rule
+type suppressions map[string][]string
+
+// collectSuppressions finds nolint directives in def and field descriptions
+func collectSuppressions(s *Schema) suppressions {
+	out := make(suppressions)
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		if rules, ok := parseNolint(def.Description); ok {
+			out[name] = rules
+		}
+		walkFields(&def, "", func(path string, field *Field) {
+			if rules, ok := parseNolint(field.Description); ok {
+				out[name+"."+path] = rules
+			}
+			if rules, ok := parseNolint(field.Def.Description); ok {
+				out[name+"."+path] = append(out[name+"."+path], rules...)
+			}
+		})
+	}
+	return out
+}
+
+// suppressed reports whether a directive on the issue's def or any enclosing field covers it
+func (s suppressions) suppressed(issue Issue) bool {
+	key := issue.Def
+	candidates := []string{key}
+	if issue.Path != "" {
+		for _, part := range strings.Split(issue.Path, ".") {
+			key += "." + part
+			candidates = append(candidates, key)
+		}
+	}
+
+	for _, candidate := range candidates {
+		rules, ok := s[candidate]
+		if !ok {
+			continue
+		}
+		if len(rules) == 0 || slices.Contains(rules, issue.Rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNolint extracts the rules named by a nolint directive in a description
+func parseNolint(desc *string) ([]string, bool) {
+	if desc == nil {
+		return nil, false
+	}
+	for _, word := range strings.Fields(*desc) {
+		if word == nolintDirective {
+			return []string{}, true
+		}
+		if rules, ok := strings.CutPrefix(word, nolintDirective+":"); ok {
+			return strings.Split(rules, ","), true
+		}
+	}
+	return nil, false
+}
+
+// walkFields calls fn for every field nested under a def, depth first, with the
+// dotted path of field names leading to it
+func walkFields(d *Def, prefix string, fn func(path string, field *Field)) {
+	for i := range d.Children {
+		field := &d.Children[i]
+		path := field.Name
+		if prefix != "" {
+			path = prefix + "." + field.Name
+		}
+		fn(path, field)
+		walkFields(&field.Def, path, fn)
+	}
+}
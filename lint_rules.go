@@ -0,0 +1,191 @@
+package cuessz
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultLinters returns fresh instances of the built-in lint rules
+func DefaultLinters() []Linter {
+	return []Linter{
+		&defNamingRule{pattern: regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)},
+		&fieldNamingRule{pattern: regexp.MustCompile(`^[a-z][a-z0-9_]*$`)},
+		&missingDescriptionRule{},
+		&largeLimitRule{max: 1 << 30},
+		&deprecatedTypeRule{types: []string{"Transfer"}},
+	}
+}
+
+// defNamingRule checks def names against a pattern (PascalCase by default)
+type defNamingRule struct {
+	pattern *regexp.Regexp
+}
+
+func (r *defNamingRule) Name() string              { return "def-naming" }
+func (r *defNamingRule) DefaultSeverity() Severity { return SeverityWarning }
+
+func (r *defNamingRule) Configure(options map[string]any) error {
+	return configurePattern(options, &r.pattern)
+}
+
+func (r *defNamingRule) Lint(s *Schema) []Issue {
+	var issues []Issue
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		if !r.pattern.MatchString(name) {
+			issues = append(issues, Issue{Def: name, Message: fmt.Sprintf("def name does not match %s", r.pattern)})
+		}
+	}
+	return issues
+}
+
+// fieldNamingRule checks field names against a pattern (snake_case by default)
+type fieldNamingRule struct {
+	pattern *regexp.Regexp
+}
+
+func (r *fieldNamingRule) Name() string              { return "field-naming" }
+func (r *fieldNamingRule) DefaultSeverity() Severity { return SeverityWarning }
+
+func (r *fieldNamingRule) Configure(options map[string]any) error {
+	return configurePattern(options, &r.pattern)
+}
+
+func (r *fieldNamingRule) Lint(s *Schema) []Issue {
+	var issues []Issue
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		walkFields(&def, "", func(path string, field *Field) {
+			if !r.pattern.MatchString(field.Name) {
+				issues = append(issues, Issue{Def: name, Path: path, Message: fmt.Sprintf("field name does not match %s", r.pattern)})
+			}
+		})
+	}
+	return issues
+}
+
+// missingDescriptionRule reports public defs (names starting with an upper case
+// letter) that have no description
+type missingDescriptionRule struct{}
+
+func (r *missingDescriptionRule) Name() string                   { return "missing-description" }
+func (r *missingDescriptionRule) DefaultSeverity() Severity      { return SeverityInfo }
+func (r *missingDescriptionRule) Configure(map[string]any) error { return nil }
+
+func (r *missingDescriptionRule) Lint(s *Schema) []Issue {
+	var issues []Issue
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		if first, _ := utf8.DecodeRuneInString(name); !unicode.IsUpper(first) {
+			continue
+		}
+		if def.Description == nil || *def.Description == "" {
+			issues = append(issues, Issue{Def: name, Message: "public def has no description"})
+		}
+	}
+	return issues
+}
+
+// largeLimitRule reports lists and bitlists whose limit exceeds a maximum
+type largeLimitRule struct {
+	max uint64
+}
+
+func (r *largeLimitRule) Name() string              { return "large-limit" }
+func (r *largeLimitRule) DefaultSeverity() Severity { return SeverityWarning }
+
+func (r *largeLimitRule) Configure(options map[string]any) error {
+	v, ok := options["max"]
+	if !ok {
+		return nil
+	}
+	max, ok := v.(int)
+	if !ok || max <= 0 {
+		return fmt.Errorf("option 'max' must be a positive integer, got %v", v)
+	}
+	r.max = uint64(max)
+	return nil
+}
+
+func (r *largeLimitRule) Lint(s *Schema) []Issue {
+	var issues []Issue
+	check := func(name, path string, d *Def) {
+		if (d.Type == TypeList || d.Type == TypeBitList || d.Type == TypeByteList) && d.Limit > r.max {
+			issues = append(issues, Issue{Def: name, Path: path, Message: fmt.Sprintf("limit %d exceeds %d", d.Limit, r.max)})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		check(name, "", &def)
+		walkFields(&def, "", func(path string, field *Field) {
+			check(name, path, &field.Def)
+		})
+	}
+	return issues
+}
+
+// deprecatedTypeRule reports deprecated defs (Transfer by default) and every field
+// that references one
+type deprecatedTypeRule struct {
+	types []string
+}
+
+func (r *deprecatedTypeRule) Name() string              { return "deprecated-type" }
+func (r *deprecatedTypeRule) DefaultSeverity() Severity { return SeverityWarning }
+
+func (r *deprecatedTypeRule) Configure(options map[string]any) error {
+	v, ok := options["types"]
+	if !ok {
+		return nil
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return fmt.Errorf("option 'types' must be a list of def names, got %v", v)
+	}
+	r.types = nil
+	for _, item := range list {
+		name, ok := item.(string)
+		if !ok {
+			return fmt.Errorf("option 'types' must be a list of def names, got %v", v)
+		}
+		r.types = append(r.types, name)
+	}
+	return nil
+}
+
+func (r *deprecatedTypeRule) Lint(s *Schema) []Issue {
+	var issues []Issue
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		if slices.Contains(r.types, name) {
+			issues = append(issues, Issue{Def: name, Message: "def is deprecated"})
+		}
+		walkFields(&def, "", func(path string, field *Field) {
+			if field.Def.Type == TypeRef && slices.Contains(r.types, field.Def.Ref) {
+				issues = append(issues, Issue{Def: name, Path: path, Message: fmt.Sprintf("references deprecated def '%s'", field.Def.Ref)})
+			}
+		})
+	}
+	return issues
+}
+
+// configurePattern replaces a naming pattern from the 'pattern' option
+func configurePattern(options map[string]any, pattern **regexp.Regexp) error {
+	v, ok := options["pattern"]
+	if !ok {
+		return nil
+	}
+	expr, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("option 'pattern' must be a string, got %v", v)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("option 'pattern': %w", err)
+	}
+	*pattern = re
+	return nil
+}
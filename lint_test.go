@@ -0,0 +1,147 @@
+package cuessz
+
+import (
+	"strings"
+	"testing"
+)
+
+var lintSchema = []byte(`{
+	"version": "1.0.0",
+	"defs": {
+		"bytes32": {"type": "bytevector", "size": 32},
+		"Transfer": {
+			"type": "container",
+			"description": "Removed before phase0 launch",
+			"children": [
+				{"name": "amount", "def": {"type": "uint64"}}
+			]
+		},
+		"Block": {
+			"type": "container",
+			"description": "A block",
+			"children": [
+				{"name": "parentRoot", "def": {"type": "ref", "ref": "bytes32"}},
+				{"name": "transfers", "def": {
+					"type": "list",
+					"limit": 4294967296,
+					"children": [{"name": "element", "def": {"type": "ref", "ref": "Transfer"}}]
+				}, "description": "Legacy field nolint:large-limit"}
+			]
+		}
+	}
+}`)
+
+func lintIssues(t *testing.T, cfg string) []Issue {
+	t.Helper()
+	schema, err := ParseJSON(lintSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	config, err := ParseConfig([]byte(cfg))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	issues, err := Lint(schema, DefaultLinters(), config.Lint)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	return issues
+}
+
+func findIssue(issues []Issue, rule, def, path string) *Issue {
+	for i := range issues {
+		if issues[i].Rule == rule && issues[i].Def == def && issues[i].Path == path {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+func TestLint_DefaultRules(t *testing.T) {
+	issues := lintIssues(t, "")
+
+	tests := []struct {
+		rule, def, path string
+		severity        Severity
+	}{
+		{"def-naming", "bytes32", "", SeverityWarning},
+		{"field-naming", "Block", "parentRoot", SeverityWarning},
+		{"deprecated-type", "Transfer", "", SeverityWarning},
+		{"deprecated-type", "Block", "transfers.element", SeverityWarning},
+	}
+	for _, tt := range tests {
+		issue := findIssue(issues, tt.rule, tt.def, tt.path)
+		if issue == nil {
+			t.Errorf("expected %s issue on %s %q, got: %v", tt.rule, tt.def, tt.path, issues)
+			continue
+		}
+		if issue.Severity != tt.severity {
+			t.Errorf("expected %s severity %s, got %s", tt.rule, tt.severity, issue.Severity)
+		}
+	}
+
+	// bytes32 is not public, Block and Transfer have descriptions
+	for _, issue := range issues {
+		if issue.Rule == "missing-description" {
+			t.Errorf("unexpected missing-description issue: %s", issue)
+		}
+	}
+
+	// Suppressed by the nolint directive on the field description
+	if issue := findIssue(issues, "large-limit", "Block", "transfers"); issue != nil {
+		t.Errorf("expected large-limit to be suppressed, got: %s", issue)
+	}
+}
+
+func TestLint_Config(t *testing.T) {
+	issues := lintIssues(t, `
+lint:
+  rules:
+    def-naming: off
+    field-naming: error
+    deprecated-type:
+      severity: info
+      options:
+        types: [Block]
+`)
+
+	if issue := findIssue(issues, "def-naming", "bytes32", ""); issue != nil {
+		t.Errorf("expected def-naming to be disabled, got: %s", issue)
+	}
+	if issue := findIssue(issues, "field-naming", "Block", "parentRoot"); issue == nil || issue.Severity != SeverityError {
+		t.Errorf("expected field-naming error, got: %v", issue)
+	}
+	if issue := findIssue(issues, "deprecated-type", "Block", ""); issue == nil || issue.Severity != SeverityInfo {
+		t.Errorf("expected Block to be deprecated at info, got: %v", issue)
+	}
+	if issue := findIssue(issues, "deprecated-type", "Transfer", ""); issue != nil {
+		t.Errorf("expected Transfer to no longer be deprecated, got: %s", issue)
+	}
+}
+
+func TestLint_ConfigErrors(t *testing.T) {
+	schema, err := ParseJSON(lintSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	if _, err := ParseConfig([]byte("lint:\n  rules:\n    def-naming: loud\n")); err == nil {
+		t.Error("expected error for unknown severity, got nil")
+	}
+
+	config, err := ParseConfig([]byte("lint:\n  rules:\n    no-such-rule: error\n"))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if _, err := Lint(schema, DefaultLinters(), config.Lint); err == nil || !strings.Contains(err.Error(), "no-such-rule") {
+		t.Errorf("expected error for unknown rule, got: %v", err)
+	}
+
+	config, err = ParseConfig([]byte("lint:\n  rules:\n    large-limit:\n      options:\n        max: lots\n"))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if _, err := Lint(schema, DefaultLinters(), config.Lint); err == nil {
+		t.Error("expected error for invalid rule option, got nil")
+	}
+}
@@ -0,0 +1,295 @@
+package cuessz
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+)
+
+// bytesPerChunk is the size of a merkle tree leaf
+const bytesPerChunk = 32
+
+// maxTreeDepth is the depth of the deepest tree a limit can need (2^64 leaves)
+const maxTreeDepth = 64
+
+// Root is a hash tree root
+type Root [bytesPerChunk]byte
+
+// FieldRoot is the hash tree root of one field of a container value
+type FieldRoot struct {
+	Name string
+	Root Root
+}
+
+// zeroHashes[i] is the root of a tree of depth i with all zero leaves
+var zeroHashes = func() [maxTreeDepth + 1]Root {
+	var out [maxTreeDepth + 1]Root
+	for i := 1; i <= maxTreeDepth; i++ {
+		out[i] = hashPair(out[i-1], out[i-1])
+	}
+	return out
+}()
+
+// HashTreeRoot computes the hash_tree_root of a value of the named def (see Value)
+func (s *Schema) HashTreeRoot(def string, v Value) (Root, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return Root{}, err
+	}
+	return s.hashTreeRoot(d, v, def)
+}
+
+// FieldRoots computes the hash_tree_root of each field of a container value,
+// in field order, e.g. to check a header's body_root against a body
+func (s *Schema) FieldRoots(def string, v Value) ([]FieldRoot, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	d, err = s.resolveRef(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", def, err)
+	}
+	if d.Type != TypeContainer && d.Type != TypeProgressiveContainer {
+		return nil, fmt.Errorf("%s: expected a container, got %s", def, d.Type)
+	}
+	roots, err := s.fieldRoots(d, v, def)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FieldRoot, len(roots))
+	for i, root := range roots {
+		out[i] = FieldRoot{Name: d.Children[i].Name, Root: root}
+	}
+	return out, nil
+}
+
+// hashTreeRoot merkleizes v following the SSZ spec for the type of d
+func (s *Schema) hashTreeRoot(d *Def, v Value, path string) (Root, error) {
+	d, err := s.resolveRef(d)
+	if err != nil {
+		return Root{}, fmt.Errorf("%s: %w", path, err)
+	}
+	fail := func(format string, args ...any) (Root, error) {
+		return Root{}, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64, TypeUint128, TypeUint256, TypeBoolean:
+		data, err := s.encodeSSZ(nil, d, v, path)
+		if err != nil {
+			return Root{}, err
+		}
+		var root Root
+		copy(root[:], data)
+		return root, nil
+	case TypeBitVector, TypeBitList:
+		bits, ok := v.([]bool)
+		if !ok {
+			return fail("expected []bool, got %T", v)
+		}
+		if d.Type == TypeBitVector {
+			if uint64(len(bits)) != d.Size {
+				return fail("expected %d bits, got %d", d.Size, len(bits))
+			}
+			return merkleize(packChunks(packBits(bits)), chunkCount(d.Size, 256)), nil
+		}
+		if uint64(len(bits)) > d.Limit {
+			return fail("%d bits exceed the limit of %d", len(bits), d.Limit)
+		}
+		root := merkleize(packChunks(packBits(bits)), chunkCount(d.Limit, 256))
+		return mixInUint(root, uint64(len(bits))), nil
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		return s.sequenceRoot(d, v, path)
+	case TypeContainer:
+		roots, err := s.fieldRoots(d, v, path)
+		if err != nil {
+			return Root{}, err
+		}
+		return merkleize(roots, uint64(len(roots))), nil
+	case TypeProgressiveContainer:
+		roots, err := s.fieldRoots(d, v, path)
+		if err != nil {
+			return Root{}, err
+		}
+		// Inactive positions keep a zero leaf, so field positions never move
+		leaves := make([]Root, len(d.ActiveFields))
+		next := 0
+		for i, active := range d.ActiveFields {
+			if active == 1 {
+				leaves[i] = roots[next]
+				next++
+			}
+		}
+		activeBits := make([]bool, len(d.ActiveFields))
+		for i, active := range d.ActiveFields {
+			activeBits[i] = active == 1
+		}
+		var activeChunk Root
+		copy(activeChunk[:], packBits(activeBits))
+		return hashPair(merkleizeProgressive(leaves, 1), activeChunk), nil
+	case TypeUnion:
+		u, ok := v.(UnionValue)
+		if !ok {
+			return fail("expected UnionValue, got %T", v)
+		}
+		if u.Selector < 0 || u.Selector >= len(d.Children) {
+			return fail("union selector %d out of range (%d options)", u.Selector, len(d.Children))
+		}
+		option := &d.Children[u.Selector]
+		root, err := s.hashTreeRoot(&option.Def, u.Value, path+"."+option.Name)
+		if err != nil {
+			return Root{}, err
+		}
+		return mixInUint(root, uint64(u.Selector)), nil
+	default:
+		return fail("invalid type '%s'", d.Type)
+	}
+}
+
+// fieldRoots returns the roots of the fields of a container value, in field order
+func (s *Schema) fieldRoots(d *Def, v Value, path string) ([]Root, error) {
+	fields, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected map[string]any, got %T", path, v)
+	}
+	if err := checkFieldNames(d, fields); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	roots := make([]Root, len(d.Children))
+	for i := range d.Children {
+		child := &d.Children[i]
+		root, err := s.hashTreeRoot(&child.Def, fields[child.Name], path+"."+child.Name)
+		if err != nil {
+			return nil, err
+		}
+		roots[i] = root
+	}
+	return roots, nil
+}
+
+// sequenceRoot merkleizes a vector or list: basic elements are packed into
+// chunks, composite elements contribute one root each, and lists mix in their length
+func (s *Schema) sequenceRoot(d *Def, v Value, path string) (Root, error) {
+	isList := d.Type == TypeList || d.Type == TypeByteList
+	bound := d.Size
+	if isList {
+		bound = d.Limit
+	}
+
+	// Byte arrays may be shorthand without an element field
+	elem := &Def{Type: TypeUint8}
+	var err error
+	if !d.IsBytes() {
+		if elem, err = s.resolveRef(&d.Children[0].Def); err != nil {
+			return Root{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var root Root
+	var length uint64
+	if size := uintBits(elem.Type) / 8; size > 0 || elem.Type == TypeBoolean {
+		if elem.Type == TypeBoolean {
+			size = 1
+		}
+		// The serialization of a basic sequence is its elements back to back
+		data, err := s.encodeSSZ(nil, d, v, path)
+		if err != nil {
+			return Root{}, err
+		}
+		length = uint64(len(data) / size)
+		root = merkleize(packChunks(data), chunkCount(bound, uint64(bytesPerChunk/size)))
+	} else {
+		items, ok := v.([]any)
+		if !ok {
+			return Root{}, fmt.Errorf("%s: expected []any, got %T", path, v)
+		}
+		if err := checkLength(d, uint64(len(items)), "elements"); err != nil {
+			return Root{}, fmt.Errorf("%s: %v", path, err)
+		}
+		roots := make([]Root, len(items))
+		for i := range items {
+			if roots[i], err = s.hashTreeRoot(elem, items[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return Root{}, err
+			}
+		}
+		length = uint64(len(items))
+		root = merkleize(roots, bound)
+	}
+
+	if isList {
+		return mixInUint(root, length), nil
+	}
+	return root, nil
+}
+
+// chunkCount returns how many chunks n items need when perChunk fit in one chunk
+func chunkCount(n, perChunk uint64) uint64 {
+	return n/perChunk + min(n%perChunk, 1)
+}
+
+// packChunks splits data into zero-padded chunks
+func packChunks(data []byte) []Root {
+	chunks := make([]Root, (len(data)+bytesPerChunk-1)/bytesPerChunk)
+	for i := range chunks {
+		copy(chunks[i][:], data[i*bytesPerChunk:])
+	}
+	return chunks
+}
+
+// merkleize computes the root of a binary tree with room for limit chunks,
+// padding with zero chunks; the caller guarantees len(chunks) <= limit
+func merkleize(chunks []Root, limit uint64) Root {
+	depth := 0
+	if limit > 1 {
+		depth = bits.Len64(limit - 1)
+	}
+	if len(chunks) == 0 {
+		return zeroHashes[depth]
+	}
+
+	layer := append([]Root(nil), chunks...)
+	for i := range depth {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[i])
+		}
+		for j := range len(layer) / 2 {
+			layer[j] = hashPair(layer[2*j], layer[2*j+1])
+		}
+		layer = layer[:len(layer)/2]
+	}
+	return layer[0]
+}
+
+// merkleizeProgressive computes the root of a progressive tree (EIP-7916): the
+// first numLeaves chunks form a binary subtree on the right, and the rest
+// recurse on the left with four times as many leaves
+func merkleizeProgressive(chunks []Root, numLeaves uint64) Root {
+	if len(chunks) == 0 {
+		return Root{}
+	}
+	n := min(uint64(len(chunks)), numLeaves)
+	return hashPair(merkleizeProgressive(chunks[n:], numLeaves*4), merkleize(chunks[:n], numLeaves))
+}
+
+// mixInUint hashes a root with a little-endian uint256 (a list length or union selector)
+func mixInUint(root Root, n uint64) Root {
+	var chunk Root
+	appendUint(chunk[:0], n, 8)
+	return hashPair(root, chunk)
+}
+
+func hashPair(a, b Root) Root {
+	h := sha256.New()
+	h.Write(a[:])
+	h.Write(b[:])
+	var out Root
+	h.Sum(out[:0])
+	return out
+}
+
+// String returns the root as 0x-prefixed hex
+func (r Root) String() string {
+	return "0x" + hex.EncodeToString(r[:])
+}
@@ -0,0 +1,111 @@
+package cuessz
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+// node hashes two chunks independently of hashPair
+func node(a, b Root) Root {
+	return sha256.Sum256(append(a[:], b[:]...))
+}
+
+// leaf returns a zero-padded chunk starting with data
+func leaf(data ...byte) Root {
+	var out Root
+	copy(out[:], data)
+	return out
+}
+
+func TestHashTreeRoot(t *testing.T) {
+	schema := sszSchema(t)
+	schema.Defs["Progressive"] = ProgressiveContainer("Progressive", 1, 0, 1).
+		Field("a", Uint8()).
+		Field("b", Uint8()).
+		Def()
+	schema.Defs["Bits"] = BitList(16)
+	schema.Defs["ShortBytes"] = Def{Type: TypeByteList, Limit: 4}
+
+	var zero Root
+	element := node(leaf(7), leaf(1)) // bytelist [7]: one chunk, length 1
+	tests := []struct {
+		name  string
+		def   string
+		value Value
+		want  Root
+	}{
+		{"zero checkpoint", "Checkpoint", map[string]any{"epoch": uint64(0), "root": []byte{0, 0, 0, 0}},
+			node(zero, zero)},
+		{"checkpoint", "Checkpoint", map[string]any{"epoch": uint64(0x0102), "root": []byte{1, 2, 3, 4}},
+			node(leaf(2, 1), leaf(1, 2, 3, 4))},
+		// Two uint32s pack into a single chunk
+		{"basic vector", "Counts", []any{uint64(1), uint64(2)},
+			leaf(1, 0, 0, 0, 2)},
+		// Limit 3 rounds up to 4 leaves before the length is mixed in
+		{"composite list", "Nested", []any{[]byte{7}},
+			node(node(node(element, zero), node(zero, zero)), leaf(1))},
+		{"empty list", "Nested", []any{},
+			node(node(node(zero, zero), node(zero, zero)), leaf(0))},
+		{"shorthand bytelist", "ShortBytes", []byte{7}, element},
+		{"bitlist", "Bits", []bool{true, false, true},
+			node(leaf(0b101), leaf(3))},
+		{"union", "Choice", UnionValue{Selector: 1, Value: map[string]any{"epoch": uint64(0), "root": []byte{0, 0, 0, 0}}},
+			node(node(zero, zero), leaf(1))},
+		// Leaves [a, 0, b]: a alone on the right, then [0, b] in a 4-leaf subtree on the left
+		{"progressive container", "Progressive", map[string]any{"a": uint64(1), "b": uint64(2)},
+			node(node(node(zero, node(node(zero, leaf(2)), node(zero, zero))), leaf(1)), leaf(0b101))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schema.HashTreeRoot(tt.def, tt.value)
+			if err != nil {
+				t.Fatalf("HashTreeRoot failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HashTreeRoot = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldRoots(t *testing.T) {
+	schema := sszSchema(t)
+
+	value := mixedValue()
+	roots, err := schema.FieldRoots("Mixed", value)
+	if err != nil {
+		t.Fatalf("FieldRoots failed: %v", err)
+	}
+	if len(roots) != 7 || roots[0].Name != "flags" || roots[0].Root != leaf(7) {
+		t.Fatalf("unexpected field roots: %v", roots)
+	}
+
+	// The container root is the merkleization of its field roots
+	leaves := make([]Root, len(roots))
+	for i, r := range roots {
+		leaves[i] = r.Root
+	}
+	want := node(
+		node(node(leaves[0], leaves[1]), node(leaves[2], leaves[3])),
+		node(node(leaves[4], leaves[5]), node(leaves[6], Root{})),
+	)
+	if got, err := schema.HashTreeRoot("Mixed", value); err != nil || got != want {
+		t.Errorf("HashTreeRoot = %s, %v; want %s", got, err, want)
+	}
+
+	if _, err := schema.FieldRoots("Counts", []any{uint64(1), uint64(2)}); err == nil || !strings.Contains(err.Error(), "expected a container") {
+		t.Errorf("expected container error, got: %v", err)
+	}
+}
+
+func TestZeroHashes(t *testing.T) {
+	// Well-known root of a depth-1 zero tree
+	if got := zeroHashes[1].String(); got != "0xf5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b" {
+		t.Errorf("zeroHashes[1] = %s", got)
+	}
+	if merkleize(nil, 1<<40) != zeroHashes[40] {
+		t.Error("merkleize of no chunks should be the zero hash of the tree depth")
+	}
+}
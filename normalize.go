@@ -0,0 +1,135 @@
+package cuessz
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/token"
+	cueJSON "cuelang.org/go/encoding/json"
+)
+
+// defaultSchemaVersion matches the default version in ssz_schema.cue
+const defaultSchemaVersion = "1.0.0"
+
+// Normalize rewrites the schema in place into its canonical form:
+// shorthand types are expanded, empty descriptions and metadata are removed
+// and the default version is filled in
+// Fields that have no meaning for a def's type (e.g. size on a container) are
+// dropped too, which only matters for schemas written as Go struct literals:
+// ParseJSON and Build reject such fields
+// Defs are kept in a map, so the JSON and CUE writers emit them sorted by name
+func (s *Schema) Normalize() {
+	if s.Version == "" {
+		s.Version = defaultSchemaVersion
+	}
+	if len(s.Constants) == 0 {
+		s.Constants = nil
+	}
+	if s.Metadata != nil && s.Metadata.Namespace == "" && s.Metadata.Description == "" && len(s.Metadata.Authors) == 0 {
+		s.Metadata = nil
+	}
+
+	s.ExpandShorthand()
+	for name, def := range s.Defs {
+		normalizeDef(&def)
+		s.Defs[name] = def
+	}
+}
+
+// normalizeDef drops the fields of a def (and its children) that its type does not
+// use, which only struct literals can set
+func normalizeDef(d *Def) {
+	switch d.Type {
+	case TypeVector, TypeBitVector:
+	default:
+		d.Size, d.SizeExpr = 0, ""
+	}
+
+	switch d.Type {
+	case TypeList, TypeBitList:
+	default:
+		d.Limit, d.LimitExpr = 0, ""
+	}
+
+	if d.Type != TypeRef {
+		d.Ref = ""
+	}
+
+	switch d.Type {
+	case TypeContainer, TypeProgressiveContainer, TypeUnion, TypeVector, TypeList:
+	default:
+		d.Children = nil
+	}
+
+	if d.Type != TypeProgressiveContainer {
+		d.ActiveFields = nil
+	}
+
+	d.Description = normalizeDescription(d.Description)
+	for i := range d.Children {
+		d.Children[i].Description = normalizeDescription(d.Children[i].Description)
+		normalizeDef(&d.Children[i].Def)
+	}
+}
+
+// normalizeDescription drops empty descriptions
+func normalizeDescription(desc *string) *string {
+	if desc == nil || *desc == "" {
+		return nil
+	}
+	return desc
+}
+
+// CanonicalJSON returns the indented JSON encoding of the normalized schema
+// The schema itself is left untouched
+func (s *Schema) CanonicalJSON() ([]byte, error) {
+	normalized := s.Clone()
+	normalized.Normalize()
+	return normalized.FormatJSON()
+}
+
+// FormatJSON renders the schema as indented JSON
+// The schema is written as given; call Normalize (and optionally CollapseShorthand) first
+func (s *Schema) FormatJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// FormatCUE renders the schema as a CUE file in package pkg declaring
+// `name: cuessz.#Schema & {...}`, matching the layout of the files under specs/
+// The schema is written as given; call Normalize (and optionally CollapseShorthand) first
+func (s *Schema) FormatCUE(pkg, name string) ([]byte, error) {
+	// Indented JSON carries line breaks into the CUE positions, so structs are
+	// written one field per line like the hand-written specs
+	data, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	expr, err := cueJSON.Extract(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert schema to CUE: %w", err)
+	}
+
+	file := &ast.File{
+		Decls: []ast.Decl{
+			&ast.Package{Name: ast.NewIdent(pkg)},
+			&ast.ImportDecl{Specs: []*ast.ImportSpec{ast.NewImport(nil, "github.com/gfx-labs/cuessz")}},
+			&ast.Field{
+				Label: ast.NewIdent(name),
+				Value: ast.NewBinExpr(token.AND, ast.NewSel(ast.NewIdent("cuessz"), "#Schema"), expr),
+			},
+		},
+	}
+
+	out, err := format.Node(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format CUE: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,135 @@
+package cuessz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	empty := ""
+	schema := &Schema{
+		Metadata: &Metadata{},
+		Defs: map[string]Def{
+			"Root": {Type: TypeByteVector, Size: 32},
+			"Checkpoint": {
+				Type:         TypeContainer,
+				Size:         40,
+				Limit:        1,
+				Ref:          "Root",
+				ActiveFields: []int{1, 1},
+				Description:  &empty,
+				Children: []Field{
+					{Name: "epoch", Def: Def{Type: TypeUint64, Size: 8, Children: []Field{byteElement()}}},
+					{Name: "root", Def: Def{Type: TypeRef, Ref: "Root", Limit: 3}, Description: &empty},
+				},
+			},
+		},
+	}
+
+	schema.Normalize()
+
+	if schema.Version != defaultSchemaVersion {
+		t.Errorf("expected default version %s, got %q", defaultSchemaVersion, schema.Version)
+	}
+	if schema.Metadata != nil {
+		t.Errorf("expected empty metadata to be dropped, got %+v", schema.Metadata)
+	}
+
+	root := schema.Defs["Root"]
+	if root.Type != TypeVector || len(root.Children) != 1 {
+		t.Errorf("expected Root shorthand to be expanded, got %+v", root)
+	}
+
+	cp := schema.Defs["Checkpoint"]
+	if cp.Size != 0 || cp.Limit != 0 || cp.Ref != "" || cp.ActiveFields != nil || cp.Description != nil {
+		t.Errorf("expected redundant container fields to be dropped, got %+v", cp)
+	}
+	epoch := cp.Children[0].Def
+	if epoch.Size != 0 || epoch.Children != nil {
+		t.Errorf("expected redundant uint64 fields to be dropped, got %+v", epoch)
+	}
+	root2 := cp.Children[1]
+	if root2.Def.Limit != 0 || root2.Def.Ref != "Root" || root2.Description != nil {
+		t.Errorf("expected ref field to keep only its ref, got %+v", root2)
+	}
+}
+
+func TestCanonicalJSON_Stable(t *testing.T) {
+	a, err := ParseJSON([]byte(`{
+		"defs": {
+			"B": {"type": "container", "children": [{"name": "x", "def": {"type": "ref", "ref": "A"}}]},
+			"A": {"type": "bytevector", "size": 4}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	b, err := ParseJSON([]byte(`{
+		"version": "1.0.0",
+		"defs": {
+			"A": {"size": 4, "children": [{"def": {"type": "uint8"}, "name": "element"}], "type": "vector"},
+			"B": {"children": [{"def": {"ref": "A", "type": "ref"}, "name": "x"}], "type": "container"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	outA, err := a.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON failed: %v", err)
+	}
+	outB, err := b.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON failed: %v", err)
+	}
+	if !bytes.Equal(outA, outB) {
+		t.Errorf("expected equivalent schemas to have identical canonical JSON:\n%s\nvs\n%s", outA, outB)
+	}
+
+	// Canonical JSON is a fixed point
+	c, err := ParseJSON(outA)
+	if err != nil {
+		t.Fatalf("ParseJSON of canonical output failed: %v", err)
+	}
+	outC, err := c.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON failed: %v", err)
+	}
+	if !bytes.Equal(outA, outC) {
+		t.Errorf("expected canonical JSON to be stable, got:\n%s\nvs\n%s", outA, outC)
+	}
+}
+
+func TestFormatCUE(t *testing.T) {
+	schema, err := ParseJSON([]byte(`{
+		"constants": {"SLOTS_PER_EPOCH": 32},
+		"defs": {
+			"Root": {"type": "bytevector", "size": 32},
+			"Roots": {"type": "vector", "size": "SLOTS_PER_EPOCH", "children": [{"name": "element", "def": {"type": "ref", "ref": "Root"}}]}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	schema.Normalize()
+	schema.CollapseShorthand()
+
+	out, err := schema.FormatCUE("example", "Example")
+	if err != nil {
+		t.Fatalf("FormatCUE failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"package example",
+		`import "github.com/gfx-labs/cuessz"`,
+		"Example: cuessz.#Schema & {",
+		`type: "bytevector"`,
+		`size: "SLOTS_PER_EPOCH"`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected CUE output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
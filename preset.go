@@ -0,0 +1,263 @@
+package cuessz
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxSSZSize mirrors #MaxSSZSize in ssz_schema.cue (4-byte SSZ length prefixes)
+const maxSSZSize = 1 << 32
+
+// maxSSZLimit mirrors #MaxSSZLimit: limits only set the merkle depth, so
+// VALIDATOR_REGISTRY_LIMIT can be 2**40
+const maxSSZLimit = math.MaxUint64
+
+// Preset maps constant names to values, as found in consensus-specs preset files
+// (e.g. presets/mainnet/phase0.yaml)
+type Preset map[string]uint64
+
+// ParsePreset parses a YAML (or JSON) preset file into a Preset
+// Entries that are not unsigned integers (fork versions, addresses, ...) are skipped
+// since they can never size a type
+func ParsePreset(data []byte) (Preset, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse preset: %w", err)
+	}
+
+	preset := make(Preset, len(raw))
+	for name, value := range raw {
+		switch v := value.(type) {
+		case int:
+			if v >= 0 {
+				preset[name] = uint64(v)
+			}
+		case uint64:
+			preset[name] = v
+		}
+	}
+	return preset, nil
+}
+
+// WithPreset returns a copy of the schema with its constants overridden by the preset
+// and every size and limit re-resolved against the new values
+// Preset entries the schema does not declare are ignored, since preset files carry
+// many constants that have nothing to do with type shapes
+func (s *Schema) WithPreset(p Preset) (*Schema, error) {
+	out := s.Clone()
+	for name := range out.Constants {
+		if v, ok := p[name]; ok {
+			out.Constants[name] = v
+		}
+	}
+	if err := out.ResolveConstants(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResolveConstants evaluates every SizeExpr and LimitExpr in the schema against
+// Constants and stores the results in Size and Limit
+func (s *Schema) ResolveConstants() error {
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		if err := resolveDefConstants(&def, s.Constants, true); err != nil {
+			return fmt.Errorf("def '%s': %w", name, err)
+		}
+		s.Defs[name] = def
+	}
+	return nil
+}
+
+// checkConstants verifies that every constant expression in the schema resolves
+// to a valid size without modifying the schema
+func (s *Schema) checkConstants() error {
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		def := s.Defs[name]
+		if err := resolveDefConstants(&def, s.Constants, false); err != nil {
+			return fmt.Errorf("def '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// resolveDefConstants evaluates the constant expressions of a def and its children,
+// writing the results back only when apply is set
+func resolveDefConstants(d *Def, consts map[string]uint64, apply bool) error {
+	if d.SizeExpr != "" {
+		v, err := evalConstExpr(d.SizeExpr, consts, maxSSZSize)
+		if err != nil {
+			return fmt.Errorf("size: %w", err)
+		}
+		if apply {
+			d.Size = v
+		}
+	}
+	if d.LimitExpr != "" {
+		v, err := evalConstExpr(d.LimitExpr, consts, maxSSZLimit)
+		if err != nil {
+			return fmt.Errorf("limit: %w", err)
+		}
+		if apply {
+			d.Limit = v
+		}
+	}
+	for i := range d.Children {
+		if err := resolveDefConstants(&d.Children[i].Def, consts, apply); err != nil {
+			return fmt.Errorf("field '%s': %w", d.Children[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// evalConstExpr evaluates a constant expression: a product of constant names and
+// integer literals, e.g. "MAX_ATTESTATIONS * SLOTS_PER_EPOCH", to a length of at most max
+func evalConstExpr(expr string, consts map[string]uint64, max uint64) (uint64, error) {
+	result := uint64(1)
+	for _, factor := range strings.Split(expr, "*") {
+		factor = strings.TrimSpace(factor)
+
+		v, err := strconv.ParseUint(factor, 10, 64)
+		if err != nil {
+			var ok bool
+			v, ok = consts[factor]
+			if !ok {
+				return 0, fmt.Errorf("%w '%s' in '%s'", ErrUnknownConstant, factor, expr)
+			}
+		}
+
+		hi, lo := bits.Mul64(result, v)
+		if hi != 0 {
+			return 0, fmt.Errorf("'%s' overflows uint64", expr)
+		}
+		result = lo
+	}
+
+	if result == 0 || result > max {
+		return 0, fmt.Errorf("'%s' evaluates to %d - must be between 1 and %d", expr, result, max)
+	}
+	return result, nil
+}
+
+// MarshalJSON writes Size and Limit as their constant expression when one is set
+func (d Def) MarshalJSON() ([]byte, error) {
+	type plain Def
+	// Fields of the outer struct come first and shadow those of plain, so the
+	// fields up to Limit are repeated to keep the declaration order of Def
+	return json.Marshal(struct {
+		Type        TypeName `json:"type"`
+		Description *string  `json:"description,omitempty"`
+		Size        any      `json:"size,omitempty"`
+		Limit       any      `json:"limit,omitempty"`
+		plain
+	}{
+		Type:        d.Type,
+		Description: d.Description,
+		Size:        lengthOrExpr(d.Size, d.SizeExpr),
+		Limit:       lengthOrExpr(d.Limit, d.LimitExpr),
+		plain:       plain(d),
+	})
+}
+
+// lengthOrExpr is the expression of a size or limit if it has one, its value
+// if set, or nil to omit it
+func lengthOrExpr(value uint64, expr string) any {
+	switch {
+	case expr != "":
+		return expr
+	case value != 0:
+		return value
+	default:
+		return nil
+	}
+}
+
+// UnmarshalJSON accepts Size and Limit either as numbers or as constant expressions
+// Expressions are left unresolved until ResolveConstants is called
+func (d *Def) UnmarshalJSON(data []byte) error {
+	type plain Def
+	aux := struct {
+		*plain
+		Size  json.RawMessage `json:"size,omitempty"`
+		Limit json.RawMessage `json:"limit,omitempty"`
+	}{plain: (*plain)(d)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if err := parseLength(aux.Size, &d.Size, &d.SizeExpr); err != nil {
+		return fmt.Errorf("size: %w", err)
+	}
+	if err := parseLength(aux.Limit, &d.Limit, &d.LimitExpr); err != nil {
+		return fmt.Errorf("limit: %w", err)
+	}
+	return nil
+}
+
+// MarshalYAML writes Size and Limit as their constant expression when one is set
+func (d Def) MarshalYAML() (any, error) {
+	type plain Def
+	p := plain(d)
+	// Expressions overwrite the values of their keys, so unresolved ones need a key too
+	if d.SizeExpr != "" {
+		p.Size = 1
+	}
+	if d.LimitExpr != "" {
+		p.Limit = 1
+	}
+	var node yaml.Node
+	if err := node.Encode(p); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		expr := map[string]string{"size": d.SizeExpr, "limit": d.LimitExpr}[node.Content[i].Value]
+		if expr != "" {
+			node.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: expr}
+		}
+	}
+	return &node, nil
+}
+
+// UnmarshalYAML accepts Size and Limit either as numbers or as constant expressions
+// like UnmarshalJSON, leaving expressions unresolved until ResolveConstants
+func (d *Def) UnmarshalYAML(node *yaml.Node) error {
+	type plain Def
+	rest := *node
+	if node.Kind == yaml.MappingNode {
+		rest.Content = nil
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if value.Kind == yaml.ScalarNode && value.ShortTag() == "!!str" {
+				switch key.Value {
+				case "size":
+					d.SizeExpr = value.Value
+					continue
+				case "limit":
+					d.LimitExpr = value.Value
+					continue
+				}
+			}
+			rest.Content = append(rest.Content, key, value)
+		}
+	}
+	return rest.Decode((*plain)(d))
+}
+
+// parseLength decodes a raw size or limit that is either a number or an expression string
+func parseLength(raw json.RawMessage, value *uint64, expr *string) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if raw[0] == '"' {
+		return json.Unmarshal(raw, expr)
+	}
+	return json.Unmarshal(raw, value)
+}
@@ -0,0 +1,180 @@
+package cuessz
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var presetSchema = []byte(`{
+	"version": "1.0.0",
+	"constants": {
+		"MAX_VALIDATORS_PER_COMMITTEE": 2048,
+		"MAX_ATTESTATIONS": 128,
+		"SLOTS_PER_EPOCH": 32
+	},
+	"defs": {
+		"Attestation": {
+			"type": "container",
+			"children": [
+				{
+					"name": "aggregation_bits",
+					"def": {"type": "bitlist", "limit": "MAX_VALIDATORS_PER_COMMITTEE"}
+				}
+			]
+		},
+		"PendingAttestations": {
+			"type": "list",
+			"limit": "MAX_ATTESTATIONS * SLOTS_PER_EPOCH",
+			"children": [
+				{"name": "element", "def": {"type": "ref", "ref": "Attestation"}}
+			]
+		}
+	}
+}`)
+
+func TestParseJSON_Constants(t *testing.T) {
+	schema, err := ParseJSON(presetSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	bits := schema.Defs["Attestation"].Children[0].Def
+	if bits.Limit != 2048 {
+		t.Errorf("expected aggregation_bits limit 2048, got %d", bits.Limit)
+	}
+	if bits.LimitExpr != "MAX_VALIDATORS_PER_COMMITTEE" {
+		t.Errorf("expected limit expression to be kept, got %q", bits.LimitExpr)
+	}
+
+	if limit := schema.Defs["PendingAttestations"].Limit; limit != 4096 {
+		t.Errorf("expected PendingAttestations limit 4096, got %d", limit)
+	}
+}
+
+func TestWithPreset(t *testing.T) {
+	schema, err := ParseJSON(presetSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	preset, err := ParsePreset([]byte(`
+# minimal preset
+SLOTS_PER_EPOCH: 8
+MAX_VALIDATORS_PER_COMMITTEE: 2048
+PRESET_BASE: minimal
+UNRELATED_CONSTANT: 7
+`))
+	if err != nil {
+		t.Fatalf("ParsePreset failed: %v", err)
+	}
+	if _, ok := preset["PRESET_BASE"]; ok {
+		t.Errorf("expected non-integer preset entries to be skipped")
+	}
+
+	minimal, err := schema.WithPreset(preset)
+	if err != nil {
+		t.Fatalf("WithPreset failed: %v", err)
+	}
+	if limit := minimal.Defs["PendingAttestations"].Limit; limit != 1024 {
+		t.Errorf("expected minimal PendingAttestations limit 1024, got %d", limit)
+	}
+	if _, ok := minimal.Constants["UNRELATED_CONSTANT"]; ok {
+		t.Errorf("expected undeclared preset constants to be ignored")
+	}
+
+	// The original schema must be untouched
+	if limit := schema.Defs["PendingAttestations"].Limit; limit != 4096 {
+		t.Errorf("expected original schema to keep limit 4096, got %d", limit)
+	}
+}
+
+func TestUnknownConstant(t *testing.T) {
+	schema := []byte(`{
+		"version": "1.0.0",
+		"constants": {"SLOTS_PER_EPOCH": 32},
+		"defs": {
+			"Roots": {
+				"type": "vector",
+				"size": "SLOTS_PER_HISTORICAL_ROOT",
+				"children": [{"name": "element", "def": {"type": "uint64"}}]
+			}
+		}
+	}`)
+
+	_, err := ParseJSON(schema)
+	if err == nil {
+		t.Fatal("expected error for unknown constant, got nil")
+	}
+	if !errors.Is(err, ErrUnknownConstant) {
+		t.Errorf("expected ErrUnknownConstant, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "Roots") || !strings.Contains(err.Error(), "SLOTS_PER_HISTORICAL_ROOT") {
+		t.Errorf("error should mention def and constant name, got: %v", err)
+	}
+}
+
+func TestDefMarshalJSON_KeepsExpressions(t *testing.T) {
+	schema, err := ParseJSON(presetSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"limit":"MAX_ATTESTATIONS * SLOTS_PER_EPOCH"`) {
+		t.Errorf("expected limit expression in output, got: %s", data)
+	}
+	// Fields keep the declaration order of Def
+	if out := string(data); strings.Index(out, `"limit":"MAX_ATTESTATIONS`) > strings.Index(out, `"children":[{"name":"element"`) {
+		t.Errorf("expected the limit before the children, got: %s", data)
+	}
+
+	if _, err := ParseJSON(data); err != nil {
+		t.Errorf("expected marshaled schema to parse again, got: %v", err)
+	}
+}
+
+func TestDefYAML_RoundTrip(t *testing.T) {
+	schema, err := ParseJSON(presetSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	data, err := yaml.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), "limit: MAX_ATTESTATIONS * SLOTS_PER_EPOCH") {
+		t.Errorf("expected limit expression in output, got:\n%s", data)
+	}
+
+	var back Schema
+	if err := yaml.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if err := back.ResolveConstants(); err != nil {
+		t.Fatalf("ResolveConstants failed: %v", err)
+	}
+	if !reflect.DeepEqual(&back, schema) {
+		t.Errorf("round trip mismatch:\n got  %#v\n want %#v", back, *schema)
+	}
+
+	// Unresolved expressions keep their key
+	var unresolved Def
+	if err := yaml.Unmarshal([]byte("{type: list, limit: MAX_X, children: [{name: element, def: {type: uint8}}]}"), &unresolved); err != nil {
+		t.Fatal(err)
+	}
+	if unresolved.LimitExpr != "MAX_X" || unresolved.Limit != 0 {
+		t.Errorf("limit = %d, %q; expected the expression MAX_X", unresolved.Limit, unresolved.LimitExpr)
+	}
+	if out, err := yaml.Marshal(unresolved); err != nil || !strings.Contains(string(out), "limit: MAX_X") {
+		t.Errorf("expected the unresolved limit in output, got: %s, %v", out, err)
+	}
+}
@@ -0,0 +1,196 @@
+package cuessz
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DecodeOption configures Decode
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	projection *projection
+}
+
+// projection is the tree of fields to decode: all decodes the whole value,
+// otherwise only the named fields (or union options) are decoded
+type projection struct {
+	all    bool
+	fields map[string]*projection
+}
+
+// Project limits Decode to the values at the given dot-separated field paths,
+// e.g. "message.body.execution_payload.transactions". Paths pass through
+// vectors and lists to apply to every element, and name the option of a union
+// Containers in the result only hold the projected fields, other variable-size
+// sections are skipped using their offsets. Options combine, so calling
+// Project several times decodes the union of the paths
+func Project(paths ...string) DecodeOption {
+	return func(o *decodeOptions) {
+		if o.projection == nil {
+			o.projection = &projection{}
+		}
+		for _, path := range paths {
+			o.projection.add(path)
+		}
+	}
+}
+
+func (p *projection) add(path string) {
+	node := p
+	if path != "" {
+		for _, name := range strings.Split(path, ".") {
+			if node.all {
+				return
+			}
+			if node.fields == nil {
+				node.fields = map[string]*projection{}
+			}
+			child, ok := node.fields[name]
+			if !ok {
+				child = &projection{}
+				node.fields[name] = child
+			}
+			node = child
+		}
+	}
+	node.all = true
+	node.fields = nil
+}
+
+// Decode deserializes SSZ bytes into a value of the named def like UnmarshalSSZ,
+// with options such as Project. The layout of skipped sections is still checked,
+// their contents are not
+func (s *Schema) Decode(def string, data []byte, opts ...DecodeOption) (Value, error) {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	if o.projection == nil {
+		return s.decodeSSZ(d, data, 0, def)
+	}
+	if err := s.checkProjection(d, o.projection, def, 0); err != nil {
+		return nil, err
+	}
+	return s.decodeProjected(d, data, 0, def, o.projection)
+}
+
+// checkProjection checks that every projected path exists in d
+func (s *Schema) checkProjection(d *Def, p *projection, path string, depth int) error {
+	if p.all {
+		return nil
+	}
+	if depth > maxCycleDepth {
+		return fmt.Errorf("%w: def nesting exceeds %d", ErrRecursiveType, maxCycleDepth)
+	}
+	d, err := s.resolveRef(d)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	switch d.Type {
+	case TypeContainer, TypeProgressiveContainer, TypeUnion:
+		for name, sub := range p.fields {
+			i := childIndex(d, name)
+			if i < 0 {
+				return fmt.Errorf("%s: cannot project '%s', no such field", path, name)
+			}
+			if err := s.checkProjection(&d.Children[i].Def, sub, path+"."+name, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TypeVector, TypeList:
+		if !d.IsBytes() {
+			return s.checkProjection(&d.Children[0].Def, p, path+"[]", depth+1)
+		}
+	}
+	return fmt.Errorf("%s: cannot project into a %s", path, d.Type)
+}
+
+// decodeProjected decodes the projected parts of data
+func (s *Schema) decodeProjected(d *Def, data []byte, at uint64, path string, p *projection) (Value, error) {
+	if p.all {
+		return s.decodeSSZ(d, data, at, path)
+	}
+	d, err := s.resolveRef(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	size, variable, err := s.sszFixedSize(d, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := checkFixedSize(size, variable, data, at, path); err != nil {
+		return nil, err
+	}
+
+	switch d.Type {
+	case TypeContainer, TypeProgressiveContainer:
+		parts := make([]*Def, len(d.Children))
+		for i := range d.Children {
+			parts[i] = &d.Children[i].Def
+		}
+		spans, err := s.splitParts(parts, data, at, func(i int) string { return path + "." + d.Children[i].Name })
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string]any, len(p.fields))
+		for i := range d.Children {
+			child := &d.Children[i]
+			sub, ok := p.fields[child.Name]
+			if !ok {
+				continue
+			}
+			if out[child.Name], err = s.decodeProjected(&child.Def, spans[i].data, spans[i].at, path+"."+child.Name, sub); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case TypeVector, TypeList:
+		parts, err := s.sequenceParts(d, data, at, path)
+		if err != nil {
+			return nil, err
+		}
+		elemPath := func(i int) string { return fmt.Sprintf("%s[%d]", path, i) }
+		spans, err := s.splitParts(parts, data, at, elemPath)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]any, len(parts))
+		for i := range parts {
+			if out[i], err = s.decodeProjected(parts[i], spans[i].data, spans[i].at, elemPath(i), p); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case TypeUnion:
+		option, err := unionOption(d, data, at, path)
+		if err != nil {
+			return nil, err
+		}
+		u := UnionValue{Selector: int(data[0])}
+		if sub, ok := p.fields[option.Name]; ok {
+			if u.Value, err = s.decodeProjected(&option.Def, data[1:], at+1, path+"."+option.Name, sub); err != nil {
+				return nil, err
+			}
+		}
+		return u, nil
+	default:
+		return nil, fmt.Errorf("%s: cannot project into a %s", path, d.Type)
+	}
+}
+
+// childIndex returns the index of the named child of d, or -1
+func childIndex(d *Def, name string) int {
+	for i := range d.Children {
+		if d.Children[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,78 @@
+package cuessz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode_Project(t *testing.T) {
+	schema := sszSchema(t)
+	data, err := schema.MarshalSSZ("Mixed", mixedValue())
+	if err != nil {
+		t.Fatalf("MarshalSSZ failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts []DecodeOption
+		want Value
+	}{
+		{"no projection", nil, mixedValue()},
+		{"one field", []DecodeOption{Project("extra_data")}, map[string]any{"extra_data": []byte{0xca, 0xfe}}},
+		{"through a list", []DecodeOption{Project("checkpoints.epoch")},
+			map[string]any{"checkpoints": []any{map[string]any{"epoch": uint64(18446744073709551615)}}}},
+		{"combined", []DecodeOption{Project("flags"), Project("checkpoints.root", "ok")},
+			map[string]any{"flags": uint64(7), "ok": true, "checkpoints": []any{map[string]any{"root": []byte{1, 2, 3, 4}}}}},
+		{"whole wins", []DecodeOption{Project("checkpoints", "checkpoints.root")},
+			map[string]any{"checkpoints": mixedValue()["checkpoints"]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schema.Decode("Mixed", data, tt.opts...)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	choice, _ := schema.MarshalSSZ("Choice", UnionValue{Selector: 1, Value: map[string]any{"epoch": uint64(3), "root": []byte{0, 0, 0, 1}}})
+	got, err := schema.Decode("Choice", choice, Project("checkpoint.epoch"))
+	if err != nil || !reflect.DeepEqual(got, UnionValue{Selector: 1, Value: map[string]any{"epoch": uint64(3)}}) {
+		t.Errorf("Decode(Choice) = %#v, %v", got, err)
+	}
+	got, err = schema.Decode("Choice", choice, Project("count"))
+	if err != nil || !reflect.DeepEqual(got, UnionValue{Selector: 1}) {
+		t.Errorf("Decode(Choice) with the other option projected = %#v, %v", got, err)
+	}
+}
+
+func TestDecode_ProjectErrors(t *testing.T) {
+	schema := sszSchema(t)
+	data, _ := schema.MarshalSSZ("Mixed", mixedValue())
+	// The checkpoints offset at byte 42 now points before extra_data
+	corrupt := append([]byte{}, data...)
+	corrupt[42] = 47
+
+	tests := []struct {
+		path string
+		data []byte
+		want string
+	}{
+		{"nope", data, "Mixed: cannot project 'nope', no such field"},
+		{"flags.x", data, "Mixed.flags: cannot project into a uint8"},
+		{"extra_data.x", data, "Mixed.extra_data: cannot project into a list"},
+		{"checkpoints.x", data, "Mixed.checkpoints[]: cannot project 'x'"},
+		// Skipped sections still need a valid layout
+		{"flags", corrupt, "out of order"},
+	}
+	for _, tt := range tests {
+		_, err := schema.Decode("Mixed", tt.data, Project(tt.path))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Project(%s): expected error containing %q, got: %v", tt.path, tt.want, err)
+		}
+	}
+}
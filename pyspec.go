@@ -0,0 +1,565 @@
+package cuessz
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"maps"
+	"math/bits"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParsePySpec derives a schema from ethereum/consensus-specs markdown documents
+// (e.g. specs/phase0/beacon-chain.md followed by specs/altair/beacon-chain.md)
+// It reads the `class X(Container):` blocks in python code blocks, the custom types
+// table and the constant and preset tables. Documents are applied in order, so a
+// class or constant redefined by a later fork replaces the earlier one
+// Custom types aliasing a uint or boolean are inlined; the others (Root, BLSPubkey,
+// Transaction, ...) become defs of their own. Sizes written as a product of constants
+// are kept as constant expressions, other expressions are evaluated
+// The preset, if any, overrides the values from the tables. Vector sizes beyond the
+// 4-byte SSZ length prefix range are an error; list limits like
+// VALIDATOR_REGISTRY_LIMIT (2**40) are not bounded by it
+func ParsePySpec(docs [][]byte, preset Preset) (*Schema, error) {
+	p := &pySpec{
+		classes:     make(map[string]pyClass),
+		customTypes: make(map[string]string),
+		constExprs:  make(map[string]string),
+	}
+	for _, doc := range docs {
+		if err := p.parseDoc(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &pyConverter{
+		spec:      p,
+		consts:    make(map[string]uint64),
+		resolving: make(map[string]bool),
+		preset:    preset,
+		schema:    &Schema{Version: defaultSchemaVersion, Defs: make(map[string]Def)},
+	}
+	if err := c.convert(); err != nil {
+		return nil, err
+	}
+	if err := c.schema.ResolveConstants(); err != nil {
+		return nil, err
+	}
+	if err := c.schema.Validate(); err != nil {
+		return nil, err
+	}
+	return c.schema, nil
+}
+
+// pySpec holds the raw definitions collected from the markdown documents
+type pySpec struct {
+	classes     map[string]pyClass
+	customTypes map[string]string
+	constExprs  map[string]string
+}
+
+// pyClass is a python SSZ class: its fields in order and, for progressive
+// containers, the active_fields bits
+type pyClass struct {
+	fields       []pyField
+	progressive  bool
+	activeFields []int
+}
+
+type pyField struct {
+	name string
+	typ  string
+}
+
+var (
+	pyClassLine  = regexp.MustCompile(`^class (\w+)\((Container|ProgressiveContainer\(active_fields=\[([0-9, ]*)\]\))\):`)
+	pyFieldLine  = regexp.MustCompile(`^\s+(\w+): ([^#]+?)\s*(#.*)?$`)
+	pyTableCell  = regexp.MustCompile("`([^`]+)`")
+	pyConstExpr  = regexp.MustCompile(`^([A-Z][A-Z0-9_]*|[0-9]+)( *\* *([A-Z][A-Z0-9_]*|[0-9]+))*$`)
+	pyConstToken = regexp.MustCompile(`[A-Z][A-Z0-9_]*`)
+)
+
+// parseDoc collects the classes and tables of one markdown document
+func (p *pySpec) parseDoc(doc []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(doc))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		inCode bool
+		class  string
+		table  string
+		lineNo int
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			class = ""
+			continue
+		}
+
+		if inCode {
+			if class != "" {
+				if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+					continue
+				}
+				if line[0] == ' ' || line[0] == '\t' {
+					if m := pyFieldLine.FindStringSubmatch(line); m != nil {
+						c := p.classes[class]
+						c.fields = append(c.fields, pyField{name: m[1], typ: m[2]})
+						p.classes[class] = c
+					}
+					continue
+				}
+				class = ""
+			}
+			if m := pyClassLine.FindStringSubmatch(line); m != nil {
+				class = m[1]
+				c := pyClass{progressive: strings.HasPrefix(m[2], "ProgressiveContainer")}
+				if c.progressive {
+					for _, bit := range strings.Split(m[3], ",") {
+						v, err := strconv.Atoi(strings.TrimSpace(bit))
+						if err != nil {
+							return fmt.Errorf("line %d: class '%s' has invalid active_fields: %w", lineNo, class, err)
+						}
+						c.activeFields = append(c.activeFields, v)
+					}
+				}
+				// A later definition (e.g. from the next fork) replaces the earlier one
+				p.classes[class] = c
+			}
+			continue
+		}
+
+		// Tables: the header row decides whether rows are custom types or constants
+		if !strings.HasPrefix(trimmed, "|") {
+			table = ""
+			continue
+		}
+		cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+		if len(cells) < 2 {
+			continue
+		}
+		first, second := strings.TrimSpace(cells[0]), strings.TrimSpace(cells[1])
+		switch {
+		case first == "Name" && second == "SSZ equivalent":
+			table = "types"
+			continue
+		case first == "Name" && second == "Value":
+			table = "constants"
+			continue
+		}
+
+		name := pyTableCell.FindStringSubmatch(first)
+		value := pyTableCell.FindStringSubmatch(second)
+		if name == nil || value == nil {
+			continue
+		}
+		switch table {
+		case "types":
+			p.customTypes[name[1]] = value[1]
+		case "constants":
+			p.constExprs[name[1]] = value[1]
+		}
+	}
+	return scanner.Err()
+}
+
+// pyConverter turns the collected definitions into schema defs
+type pyConverter struct {
+	spec      *pySpec
+	preset    Preset
+	consts    map[string]uint64
+	resolving map[string]bool
+	schema    *Schema
+}
+
+func (c *pyConverter) convert() error {
+	for _, name := range slices.Sorted(maps.Keys(c.spec.classes)) {
+		class := c.spec.classes[name]
+		def := Def{Type: TypeContainer}
+		if class.progressive {
+			def = Def{Type: TypeProgressiveContainer, ActiveFields: class.activeFields}
+		}
+		for _, f := range class.fields {
+			d, err := c.typeDef(f.typ)
+			if err != nil {
+				return fmt.Errorf("def '%s' at field '%s': %w", name, f.name, err)
+			}
+			def.Children = append(def.Children, Field{Name: f.name, Def: d})
+		}
+		c.schema.Defs[name] = def
+	}
+	// Composite custom types are added by typeDef as they are referenced, so the
+	// ones no class uses (and whose constants may live in other documents) are left out
+	return nil
+}
+
+// typeDef converts a python SSZ type expression, e.g. List[Attestation, MAX_ATTESTATIONS]
+func (c *pyConverter) typeDef(expr string) (Def, error) {
+	name, args, err := splitPyType(expr)
+	if err != nil {
+		return Def{}, err
+	}
+
+	switch name {
+	case "uint8":
+		return Uint8(), nil
+	case "uint16":
+		return Uint16(), nil
+	case "uint32":
+		return Uint32(), nil
+	case "uint64":
+		return Uint64(), nil
+	case "uint128":
+		return Uint128(), nil
+	case "uint256":
+		return Uint256(), nil
+	case "boolean", "bit":
+		return Boolean(), nil
+	case "ByteVector", "ByteList", "Bitvector", "Bitlist":
+		if len(args) != 1 {
+			return Def{}, fmt.Errorf("%s takes 1 argument, got %d", name, len(args))
+		}
+		max := uint64(maxSSZSize)
+		if name == "ByteList" || name == "Bitlist" {
+			max = maxSSZLimit
+		}
+		n, nExpr, err := c.length(args[0], max)
+		if err != nil {
+			return Def{}, err
+		}
+		switch name {
+		case "ByteVector":
+			return Vector(Uint8(), n).WithSizeExpr(nExpr), nil
+		case "ByteList":
+			return List(Uint8(), n).WithLimitExpr(nExpr), nil
+		case "Bitvector":
+			return BitVector(n).WithSizeExpr(nExpr), nil
+		default:
+			return BitList(n).WithLimitExpr(nExpr), nil
+		}
+	case "Vector", "List":
+		if len(args) != 2 {
+			return Def{}, fmt.Errorf("%s takes 2 arguments, got %d", name, len(args))
+		}
+		elem, err := c.typeDef(args[0])
+		if err != nil {
+			return Def{}, err
+		}
+		max := uint64(maxSSZSize)
+		if name == "List" {
+			max = maxSSZLimit
+		}
+		n, nExpr, err := c.length(args[1], max)
+		if err != nil {
+			return Def{}, err
+		}
+		if name == "Vector" {
+			return Vector(elem, n).WithSizeExpr(nExpr), nil
+		}
+		return List(elem, n).WithLimitExpr(nExpr), nil
+	case "Union":
+		union := Def{Type: TypeUnion}
+		for _, arg := range args {
+			if arg == "None" {
+				return Def{}, fmt.Errorf("Union[None, ...] is not supported")
+			}
+			d, err := c.typeDef(arg)
+			if err != nil {
+				return Def{}, err
+			}
+			union.Children = append(union.Children, Field{Name: snakeCase(arg), Def: d})
+		}
+		return union, nil
+	}
+
+	if len(args) != 0 {
+		return Def{}, fmt.Errorf("unknown type '%s'", expr)
+	}
+	if size, ok := strings.CutPrefix(name, "Bytes"); ok {
+		if n, err := strconv.ParseUint(size, 10, 64); err == nil && n > 0 {
+			return ByteVector(n), nil
+		}
+	}
+	if _, ok := c.spec.classes[name]; ok {
+		return Ref(name), nil
+	}
+	if alias, ok := c.spec.customTypes[name]; ok {
+		d, err := c.typeDef(alias)
+		if err != nil {
+			return Def{}, fmt.Errorf("custom type '%s': %w", name, err)
+		}
+		// uint and boolean aliases (Slot, Gwei, ...) are inlined, the rest become defs
+		switch d.Type {
+		case TypeUint8, TypeUint16, TypeUint32, TypeUint64, TypeUint128, TypeUint256, TypeBoolean, TypeRef:
+			return d, nil
+		}
+		c.schema.Defs[name] = d
+		return Ref(name), nil
+	}
+	return Def{}, fmt.Errorf("unknown type '%s'", name)
+}
+
+// length evaluates a size or limit argument of at most max, returning the
+// constant expression to keep when it is a product of constants
+func (c *pyConverter) length(arg string, max uint64) (uint64, string, error) {
+	arg = strings.TrimSpace(arg)
+	if pyConstExpr.MatchString(arg) && pyConstToken.MatchString(arg) {
+		for _, name := range pyConstToken.FindAllString(arg, -1) {
+			v, err := c.constant(name)
+			if err != nil {
+				return 0, "", err
+			}
+			if c.schema.Constants == nil {
+				c.schema.Constants = make(map[string]uint64)
+			}
+			if v > max {
+				return 0, "", fmt.Errorf("constant '%s' = %d exceeds the SSZ size limit of %d; override it with a preset", name, v, max)
+			}
+			c.schema.Constants[name] = v
+		}
+		return 0, arg, nil
+	}
+
+	v, err := c.eval(arg)
+	if err != nil {
+		return 0, "", err
+	}
+	if v == 0 || v > max {
+		return 0, "", fmt.Errorf("'%s' = %d is not a valid SSZ length", arg, v)
+	}
+	return v, "", nil
+}
+
+// constant resolves a named constant, preferring the preset
+func (c *pyConverter) constant(name string) (uint64, error) {
+	if v, ok := c.preset[name]; ok {
+		return v, nil
+	}
+	if v, ok := c.consts[name]; ok {
+		return v, nil
+	}
+	expr, ok := c.spec.constExprs[name]
+	if !ok {
+		return 0, fmt.Errorf("%w '%s'", ErrUnknownConstant, name)
+	}
+	if c.resolving[name] {
+		return 0, fmt.Errorf("constant '%s' is defined in terms of itself", name)
+	}
+	c.resolving[name] = true
+	defer delete(c.resolving, name)
+
+	v, err := c.eval(expr)
+	if err != nil {
+		return 0, fmt.Errorf("constant '%s': %w", name, err)
+	}
+	c.consts[name] = v
+	return v, nil
+}
+
+// splitPyType splits "List[Foo, N]" into "List" and its top-level arguments
+func splitPyType(expr string) (string, []string, error) {
+	expr = strings.TrimSpace(expr)
+	open := strings.IndexByte(expr, '[')
+	if open < 0 {
+		return expr, nil, nil
+	}
+	if !strings.HasSuffix(expr, "]") {
+		return "", nil, fmt.Errorf("malformed type '%s'", expr)
+	}
+
+	var args []string
+	depth, start := 0, open+1
+	inner := expr[:len(expr)-1]
+	for i := open + 1; i < len(inner); i++ {
+		switch inner[i] {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(inner[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return "", nil, fmt.Errorf("malformed type '%s'", expr)
+	}
+	args = append(args, strings.TrimSpace(inner[start:]))
+	return strings.TrimSpace(expr[:open]), args, nil
+}
+
+// eval evaluates a python integer expression as used in the spec tables:
+// literals, constants, + - * // **, parentheses, type casts like uint64(2**11)
+// and floorlog2
+func (c *pyConverter) eval(expr string) (uint64, error) {
+	e := &pyExpr{src: expr, c: c}
+	v, err := e.sum()
+	if err != nil {
+		return 0, fmt.Errorf("cannot evaluate '%s': %w", expr, err)
+	}
+	if e.skipSpace(); e.pos != len(e.src) {
+		return 0, fmt.Errorf("cannot evaluate '%s': unexpected '%s'", expr, e.src[e.pos:])
+	}
+	return v, nil
+}
+
+// pyExpr is a recursive descent evaluator over src
+type pyExpr struct {
+	src string
+	pos int
+	c   *pyConverter
+}
+
+func (e *pyExpr) skipSpace() {
+	for e.pos < len(e.src) && e.src[e.pos] == ' ' {
+		e.pos++
+	}
+}
+
+func (e *pyExpr) accept(tok string) bool {
+	e.skipSpace()
+	if strings.HasPrefix(e.src[e.pos:], tok) {
+		e.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (e *pyExpr) sum() (uint64, error) {
+	v, err := e.product()
+	for err == nil {
+		switch {
+		case e.accept("+"):
+			var r uint64
+			if r, err = e.product(); err == nil {
+				var carry uint64
+				if v, carry = bits.Add64(v, r, 0); carry != 0 {
+					err = fmt.Errorf("overflows uint64")
+				}
+			}
+		case e.accept("-"):
+			var r uint64
+			if r, err = e.product(); err == nil {
+				if r > v {
+					err = fmt.Errorf("negative result")
+				}
+				v -= r
+			}
+		default:
+			return v, nil
+		}
+	}
+	return 0, err
+}
+
+func (e *pyExpr) product() (uint64, error) {
+	v, err := e.power()
+	for err == nil {
+		switch {
+		case e.accept("//"):
+			var r uint64
+			if r, err = e.power(); err == nil {
+				if r == 0 {
+					err = fmt.Errorf("division by zero")
+				} else {
+					v /= r
+				}
+			}
+		case e.accept("*"):
+			var r uint64
+			if r, err = e.power(); err == nil {
+				var hi uint64
+				if hi, v = bits.Mul64(v, r); hi != 0 {
+					err = fmt.Errorf("overflows uint64")
+				}
+			}
+		default:
+			return v, nil
+		}
+	}
+	return 0, err
+}
+
+func (e *pyExpr) power() (uint64, error) {
+	base, err := e.atom()
+	if err != nil || !e.accept("**") {
+		return base, err
+	}
+	exp, err := e.power()
+	if err != nil {
+		return 0, err
+	}
+	// Square and multiply, so huge exponents of 0 and 1 finish quickly
+	result := uint64(1)
+	for ; exp > 0; exp >>= 1 {
+		var hi uint64
+		if exp&1 == 1 {
+			if hi, result = bits.Mul64(result, base); hi != 0 {
+				return 0, fmt.Errorf("overflows uint64")
+			}
+		}
+		if exp > 1 {
+			if hi, base = bits.Mul64(base, base); hi != 0 {
+				return 0, fmt.Errorf("overflows uint64")
+			}
+		}
+	}
+	return result, nil
+}
+
+func (e *pyExpr) atom() (uint64, error) {
+	e.skipSpace()
+	if e.accept("(") {
+		v, err := e.sum()
+		if err != nil {
+			return 0, err
+		}
+		if !e.accept(")") {
+			return 0, fmt.Errorf("missing ')'")
+		}
+		return v, nil
+	}
+
+	start := e.pos
+	for e.pos < len(e.src) && (e.src[e.pos] == '_' || unicode.IsLetter(rune(e.src[e.pos])) || unicode.IsDigit(rune(e.src[e.pos]))) {
+		e.pos++
+	}
+	word := e.src[start:e.pos]
+	if word == "" {
+		return 0, fmt.Errorf("unexpected '%s'", e.src[e.pos:])
+	}
+
+	if unicode.IsDigit(rune(word[0])) {
+		return strconv.ParseUint(strings.ReplaceAll(word, "_", ""), 0, 64)
+	}
+
+	// Calls: floorlog2, or a type cast such as uint64(...) or Epoch(...)
+	if e.accept("(") {
+		v, err := e.sum()
+		if err != nil {
+			return 0, err
+		}
+		if !e.accept(")") {
+			return 0, fmt.Errorf("missing ')'")
+		}
+		if word == "floorlog2" {
+			if v == 0 {
+				return 0, fmt.Errorf("floorlog2(0)")
+			}
+			return uint64(bits.Len64(v) - 1), nil
+		}
+		return v, nil
+	}
+	return e.c.constant(word)
+}
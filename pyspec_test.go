@@ -0,0 +1,221 @@
+package cuessz
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var pyPhase0 = []byte("# Phase 0 -- The Beacon Chain\n" + `
+## Custom types
+
+| Name | SSZ equivalent | Description |
+| - | - | - |
+| ` + "`Slot`" + ` | ` + "`uint64`" + ` | a slot number |
+| ` + "`Epoch`" + ` | ` + "`uint64`" + ` | an epoch number |
+| ` + "`Root`" + ` | ` + "`Bytes32`" + ` | a Merkle root |
+| ` + "`BLSSignature`" + ` | ` + "`Bytes96`" + ` | a BLS12-381 signature |
+
+## Constants
+
+| Name | Value |
+| - | - |
+| ` + "`DEPOSIT_CONTRACT_TREE_DEPTH`" + ` | ` + "`uint64(2**5)`" + ` (= 32) |
+| ` + "`GENESIS_SLOT`" + ` | ` + "`Slot(0)`" + ` |
+| ` + "`DOMAIN_BEACON_PROPOSER`" + ` | ` + "`DomainType('0x00000000')`" + ` |
+
+## Preset
+
+| Name | Value |
+| - | - |
+| ` + "`MAX_VALIDATORS_PER_COMMITTEE`" + ` | ` + "`uint64(2**11)`" + ` (= 2,048) |
+| ` + "`SLOTS_PER_EPOCH`" + ` | ` + "`uint64(2**5)`" + ` (= 32) |
+| ` + "`EPOCHS_PER_ETH1_VOTING_PERIOD`" + ` | ` + "`uint64(2**6)`" + ` (= 64) |
+| ` + "`VALIDATOR_REGISTRY_LIMIT`" + ` | ` + "`uint64(2**40)`" + ` (= 1,099,511,627,776) |
+
+## Containers
+
+` + "```python" + `
+class Checkpoint(Container):
+    epoch: Epoch
+    root: Root
+` + "```" + `
+
+` + "```python" + `
+class Eth1Data(Container):
+    deposit_root: Root
+    deposit_count: uint64  # count
+    block_hash: Bytes32
+` + "```" + `
+
+` + "```python" + `
+class Deposit(Container):
+    proof: Vector[Bytes32, DEPOSIT_CONTRACT_TREE_DEPTH + 1]  # Merkle path to deposit root
+    amount: uint64
+` + "```" + `
+
+` + "```python" + `
+class BeaconState(Container):
+    slot: Slot
+    # Eth1
+    eth1_data_votes: List[Eth1Data, EPOCHS_PER_ETH1_VOTING_PERIOD * SLOTS_PER_EPOCH]
+    balances: List[uint64, VALIDATOR_REGISTRY_LIMIT]
+    justification_bits: Bitvector[4]
+    finalized_checkpoint: Checkpoint
+` + "```" + `
+
+` + "```python" + `
+def get_current_epoch(state: BeaconState) -> Epoch:
+    return compute_epoch_at_slot(state.slot)
+` + "```" + `
+`)
+
+var pyAltair = []byte(`# Altair -- The Beacon Chain
+
+## Preset
+
+| Name | Value |
+| - | - |
+| ` + "`SYNC_COMMITTEE_SIZE`" + ` | ` + "`uint64(2**9)`" + ` (= 512) |
+
+` + "```python" + `
+class SyncAggregate(Container):
+    sync_committee_bits: Bitvector[SYNC_COMMITTEE_SIZE]
+    sync_committee_signature: BLSSignature
+` + "```" + `
+
+` + "```python" + `
+class BeaconState(Container):
+    slot: Slot
+    eth1_data_votes: List[Eth1Data, EPOCHS_PER_ETH1_VOTING_PERIOD * SLOTS_PER_EPOCH]
+    balances: List[uint64, VALIDATOR_REGISTRY_LIMIT]
+    justification_bits: Bitvector[4]
+    finalized_checkpoint: Checkpoint
+    # [New in Altair]
+    inactivity_scores: List[uint64, VALIDATOR_REGISTRY_LIMIT]
+` + "```" + `
+`)
+
+func TestParsePySpec(t *testing.T) {
+	schema, err := ParsePySpec([][]byte{pyPhase0, pyAltair}, nil)
+	if err != nil {
+		t.Fatalf("ParsePySpec failed: %v", err)
+	}
+
+	want := NewSchema().
+		Constant("EPOCHS_PER_ETH1_VOTING_PERIOD", 64).
+		Constant("SLOTS_PER_EPOCH", 32).
+		Constant("SYNC_COMMITTEE_SIZE", 512).
+		Constant("VALIDATOR_REGISTRY_LIMIT", 1<<40).
+		Def("Root", ByteVector(32)).
+		Def("BLSSignature", ByteVector(96)).
+		Add(
+			Container("Checkpoint").
+				Field("epoch", Uint64()).
+				Field("root", Ref("Root")),
+			Container("Eth1Data").
+				Field("deposit_root", Ref("Root")).
+				Field("deposit_count", Uint64()).
+				Field("block_hash", ByteVector(32)),
+			Container("Deposit").
+				Field("proof", Vector(ByteVector(32), 33)).
+				Field("amount", Uint64()),
+			Container("SyncAggregate").
+				Field("sync_committee_bits", BitVector(0).WithSizeExpr("SYNC_COMMITTEE_SIZE")).
+				Field("sync_committee_signature", Ref("BLSSignature")),
+			Container("BeaconState").
+				Field("slot", Uint64()).
+				Field("eth1_data_votes", List(Ref("Eth1Data"), 0).WithLimitExpr("EPOCHS_PER_ETH1_VOTING_PERIOD * SLOTS_PER_EPOCH")).
+				Field("balances", List(Uint64(), 0).WithLimitExpr("VALIDATOR_REGISTRY_LIMIT")).
+				Field("justification_bits", BitVector(4)).
+				Field("finalized_checkpoint", Ref("Checkpoint")).
+				Field("inactivity_scores", List(Uint64(), 0).WithLimitExpr("VALIDATOR_REGISTRY_LIMIT")),
+		).
+		MustBuild()
+
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("unexpected schema:\n%s\nwant:\n%s", mustCanonical(t, schema), mustCanonical(t, want))
+	}
+}
+
+func TestParsePySpec_Preset(t *testing.T) {
+	schema, err := ParsePySpec([][]byte{pyPhase0}, Preset{"SLOTS_PER_EPOCH": 8, "EPOCHS_PER_ETH1_VOTING_PERIOD": 4})
+	if err != nil {
+		t.Fatalf("ParsePySpec failed: %v", err)
+	}
+	if got := schema.Defs["BeaconState"].Children[1].Def.Limit; got != 32 {
+		t.Errorf("expected eth1_data_votes limit 32 with the minimal preset, got %d", got)
+	}
+}
+
+func TestParsePySpec_Errors(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{"```python\nclass A(Container):\n    x: Unknown\n```\n", "unknown type 'Unknown'"},
+		{"```python\nclass A(Container):\n    x: List[uint64, MAX_THINGS]\n```\n", "MAX_THINGS"},
+		{"```python\nclass A(Container):\n    x: List[uint64]\n```\n", "List takes 2 arguments"},
+		{"```python\nclass A(Container):\n    x: Vector[uint64, 2**33]\n```\n", "not a valid SSZ length"},
+		{"```python\nclass A(Container):\n    x: B\n\nclass B(Container):\n    a: A\n```\n", "recursive"},
+	}
+	for _, tt := range tests {
+		_, err := ParsePySpec([][]byte{[]byte(tt.doc)}, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got: %v", tt.want, err)
+		}
+	}
+
+	// Constants too large for a vector size are not capped silently, but list
+	// limits may exceed the 4-byte length range
+	big := "| Name | Value |\n| - | - |\n| `BIG` | `uint64(2**40)` |\n\n```python\nclass A(Container):\n    x: %s[uint64, BIG]\n```\n"
+	if _, err := ParsePySpec([][]byte{[]byte(fmt.Sprintf(big, "Vector"))}, nil); err == nil || !strings.Contains(err.Error(), "constant 'BIG' = 1099511627776 exceeds") {
+		t.Errorf("expected an oversized constant error, got: %v", err)
+	}
+	if schema, err := ParsePySpec([][]byte{[]byte(fmt.Sprintf(big, "List"))}, nil); err != nil || schema.Defs["A"].Children[0].Def.Limit != 1<<40 {
+		t.Errorf("expected a list limit of 2**40, got: %v", err)
+	}
+
+	_, err := ParsePySpec([][]byte{[]byte("```python\nclass A(Container):\n    x: List[uint64, MAX_THINGS]\n```\n")}, nil)
+	if !errors.Is(err, ErrUnknownConstant) {
+		t.Errorf("expected ErrUnknownConstant, got: %v", err)
+	}
+}
+
+func TestPyExprEval(t *testing.T) {
+	c := &pyConverter{
+		spec:      &pySpec{constExprs: map[string]string{"A": "uint64(2**5)", "B": "A * 2", "LOOP": "LOOP + 1"}},
+		consts:    make(map[string]uint64),
+		resolving: make(map[string]bool),
+	}
+	for expr, want := range map[string]uint64{
+		"1":                        1,
+		"2**10":                    1024,
+		"2**3**2":                  512,
+		"A + 1":                    33,
+		"B // 4 - 1":               15,
+		"(A + 1) * 2":              66,
+		"floorlog2(105)":           6,
+		"Gwei(2**5 * 10**9)":       32000000000,
+		"uint64(16_777_216)":       16777216,
+		"GeneralizedIndex(0x2a)":   42,
+		"floorlog2(2**6) + B // B": 7,
+		"1 ** 2**62":               1,
+		"0 ** 2**62":               0,
+		"3 ** 40":                  12157665459056928801,
+		"2**63":                    1 << 63,
+	} {
+		got, err := c.eval(expr)
+		if err != nil || got != want {
+			t.Errorf("eval(%q) = %d, %v; want %d", expr, got, err, want)
+		}
+	}
+
+	for _, expr := range []string{"LOOP", "2**64", "3**41", "2 ** 2**62", "1 - 2", "(1", "DomainType('0x00')", "MISSING"} {
+		if _, err := c.eval(expr); err == nil {
+			t.Errorf("eval(%q): expected error", expr)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cuessz
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+)
+
+// DefaultMaxListLength caps random lists when RandomOptions.MaxListLength is 0
+const DefaultMaxListLength = 16
+
+// RandomOptions configures RandomValue
+type RandomOptions struct {
+	// MaxListLength caps the length of lists, byte lists and bitlists below
+	// their limit, so large consensus types stay small; 0 means DefaultMaxListLength
+	MaxListLength uint64
+	// EdgeBias is the chance, from 0 to 1, of picking an edge case for each value:
+	// 0 or the max uint, all-zero or all-one bytes and bits, and empty or full lists
+	EdgeBias float64
+}
+
+// RandomValue generates a valid value of the named def (see Value) from src.
+// Vectors get their size, lists up to their limit or MaxListLength, unions a
+// random option and progressive containers all their fields
+func (s *Schema) RandomValue(def string, src rand.Source, opts RandomOptions) (Value, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxListLength == 0 {
+		opts.MaxListLength = DefaultMaxListLength
+	}
+	g := &randomGen{schema: s, r: rand.New(src), opts: opts}
+	return g.value(d, def, 0)
+}
+
+type randomGen struct {
+	schema *Schema
+	r      *rand.Rand
+	opts   RandomOptions
+}
+
+// edge picks whether to generate an edge case, and which: the low or the high one
+func (g *randomGen) edge() (bool, bool) {
+	if g.opts.EdgeBias <= 0 || g.r.Float64() >= g.opts.EdgeBias {
+		return false, false
+	}
+	return true, g.r.IntN(2) == 1
+}
+
+// length picks the length of a list of the given limit
+func (g *randomGen) length(limit uint64) uint64 {
+	limit = min(limit, g.opts.MaxListLength)
+	if ok, high := g.edge(); ok {
+		if high {
+			return limit
+		}
+		return 0
+	}
+	return g.r.Uint64N(limit + 1)
+}
+
+func (g *randomGen) value(d *Def, path string, depth int) (Value, error) {
+	if depth > maxCycleDepth {
+		return nil, fmt.Errorf("%w: def nesting exceeds %d", ErrRecursiveType, maxCycleDepth)
+	}
+	d, err := g.schema.resolveRef(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64:
+		bits := uintBits(d.Type)
+		n := g.r.Uint64()
+		if ok, high := g.edge(); ok {
+			n = 0
+			if high {
+				n = ^uint64(0)
+			}
+		}
+		return n >> (64 - bits), nil
+	case TypeUint128, TypeUint256:
+		buf := g.bytes(uint64(uintBits(d.Type) / 8))
+		return new(big.Int).SetBytes(buf), nil
+	case TypeBoolean:
+		return g.r.IntN(2) == 1, nil
+	case TypeBitVector, TypeBitList:
+		n := d.Size
+		if d.Type == TypeBitList {
+			n = g.length(d.Limit)
+		}
+		bits := make([]bool, n)
+		ok, high := g.edge()
+		for i := range bits {
+			bits[i] = high || !ok && g.r.IntN(2) == 1
+		}
+		return bits, nil
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		n := d.Size
+		if d.Type == TypeList || d.Type == TypeByteList {
+			n = g.length(d.Limit)
+		}
+		if d.IsBytes() {
+			return g.bytes(n), nil
+		}
+		elems := make([]any, n)
+		for i := range elems {
+			if elems[i], err = g.value(&d.Children[0].Def, fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return elems, nil
+	case TypeContainer, TypeProgressiveContainer:
+		fields := make(map[string]any, len(d.Children))
+		for i := range d.Children {
+			child := &d.Children[i]
+			if fields[child.Name], err = g.value(&child.Def, path+"."+child.Name, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return fields, nil
+	case TypeUnion:
+		if len(d.Children) == 0 {
+			return nil, fmt.Errorf("%s: union has no options", path)
+		}
+		i := g.r.IntN(len(d.Children))
+		option := &d.Children[i]
+		v, err := g.value(&option.Def, path+"."+option.Name, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return UnionValue{Selector: i, Value: v}, nil
+	default:
+		return nil, fmt.Errorf("%s: invalid type '%s'", path, d.Type)
+	}
+}
+
+// bytes returns n random bytes, or all 0x00 or 0xff as an edge case
+func (g *randomGen) bytes(n uint64) []byte {
+	buf := make([]byte, n)
+	if ok, high := g.edge(); ok {
+		if high {
+			for i := range buf {
+				buf[i] = 0xff
+			}
+		}
+		return buf
+	}
+	for i := range buf {
+		buf[i] = byte(g.r.Uint32())
+	}
+	return buf
+}
@@ -0,0 +1,127 @@
+package cuessz
+
+import (
+	"bytes"
+	"maps"
+	"math/big"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/load"
+)
+
+// consensusSchema loads the BeaconChain schema from specs/consensus
+func consensusSchema(t *testing.T) *Schema {
+	t.Helper()
+	v := cuecontext.New().BuildInstance(load.Instances([]string{"./specs/consensus"}, nil)[0])
+	data, err := v.LookupPath(cue.ParsePath("BeaconChain")).MarshalJSON()
+	if err != nil {
+		t.Fatalf("loading specs/consensus: %v", err)
+	}
+	schema, err := ParseJSON(data)
+	if err != nil {
+		t.Fatalf("specs/consensus: %v", err)
+	}
+	return schema
+}
+
+func TestRandomValue(t *testing.T) {
+	schema := sszSchema(t)
+
+	for _, def := range slices.Sorted(maps.Keys(schema.Defs)) {
+		for seed := range uint64(20) {
+			v, err := schema.RandomValue(def, rand.NewPCG(seed, 0), RandomOptions{EdgeBias: 0.3})
+			if err != nil {
+				t.Fatalf("RandomValue(%s): %v", def, err)
+			}
+			data, err := schema.MarshalSSZ(def, v)
+			if err != nil {
+				t.Fatalf("RandomValue(%s) = %v, which does not encode: %v", def, v, err)
+			}
+			decoded, err := schema.UnmarshalSSZ(def, data)
+			if err != nil {
+				t.Fatalf("UnmarshalSSZ(%s): %v", def, err)
+			}
+			if again, _ := schema.MarshalSSZ(def, decoded); !bytes.Equal(again, data) {
+				t.Fatalf("%s: %x re-encodes to %x", def, data, again)
+			}
+		}
+	}
+
+	a, _ := schema.RandomValue("Mixed", rand.NewPCG(1, 2), RandomOptions{})
+	b, _ := schema.RandomValue("Mixed", rand.NewPCG(1, 2), RandomOptions{})
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("RandomValue is not deterministic for a seed: %v and %v", a, b)
+	}
+
+	if _, err := schema.RandomValue("Missing", rand.NewPCG(0, 0), RandomOptions{}); err == nil {
+		t.Error("expected an error for a missing def")
+	}
+}
+
+func TestRandomValue_Options(t *testing.T) {
+	schema := NewSchema().
+		Def("Longs", List(Uint64(), 1000)).
+		Def("Bits", BitList(1000)).
+		Def("Number", Uint32()).
+		Def("Big", Uint256()).
+		MustBuild()
+
+	for seed := range uint64(50) {
+		v, _ := schema.RandomValue("Longs", rand.NewPCG(seed, 0), RandomOptions{MaxListLength: 5})
+		if n := len(v.([]any)); n > 5 {
+			t.Fatalf("list of %d elements exceeds MaxListLength 5", n)
+		}
+		v, _ = schema.RandomValue("Bits", rand.NewPCG(seed, 0), RandomOptions{})
+		if n := len(v.([]bool)); n > DefaultMaxListLength {
+			t.Fatalf("bitlist of %d bits exceeds the default max length", n)
+		}
+	}
+
+	// With EdgeBias 1 every value is an edge case
+	edges := RandomOptions{MaxListLength: 7, EdgeBias: 1}
+	seen := map[any]bool{}
+	for seed := range uint64(50) {
+		v, _ := schema.RandomValue("Number", rand.NewPCG(seed, 0), edges)
+		if v != uint64(0) && v != uint64(0xffffffff) {
+			t.Fatalf("edge uint32 = %v", v)
+		}
+		seen[v] = true
+
+		v, _ = schema.RandomValue("Longs", rand.NewPCG(seed, 0), edges)
+		if n := len(v.([]any)); n != 0 && n != 7 {
+			t.Fatalf("edge list has %d elements, expected 0 or 7", n)
+		}
+
+		v, _ = schema.RandomValue("Big", rand.NewPCG(seed, 0), edges)
+		max256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+		if n := v.(*big.Int); n.Sign() != 0 && n.Cmp(max256) != 0 {
+			t.Fatalf("edge uint256 = %v", n)
+		}
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected both edges of uint32, got %v", seen)
+	}
+}
+
+func TestRandomValue_Consensus(t *testing.T) {
+	schema := consensusSchema(t)
+
+	for _, def := range slices.Sorted(maps.Keys(schema.Defs)) {
+		v, err := schema.RandomValue(def, rand.NewPCG(0, 0), RandomOptions{MaxListLength: 2, EdgeBias: 0.2})
+		if err != nil {
+			t.Fatalf("RandomValue(%s): %v", def, err)
+		}
+		data, err := schema.MarshalSSZ(def, v)
+		if err != nil {
+			t.Fatalf("RandomValue(%s) does not encode: %v", def, err)
+		}
+		if _, err := schema.UnmarshalSSZ(def, data); err != nil {
+			t.Fatalf("UnmarshalSSZ(%s): %v", def, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cuessz
+
+import (
+	"fmt"
+	"slices"
+)
+
+// collectDefRefs collects all type references from a Go def with location context
+// It mirrors collectRefsFromCUE for schemas that only exist as Go values
+func collectDefRefs(d *Def) []refLocation {
+	return collectDefRefsWithPath(d, "", nil)
+}
+
+// collectDefRefsWithPath is the internal implementation that tracks the path
+func collectDefRefsWithPath(d *Def, pathPrefix string, fields []string) []refLocation {
+	var refs []refLocation
+
+	if d.Type == TypeRef {
+		location := "type reference"
+		if pathPrefix != "" {
+			location = pathPrefix
+		}
+		refs = append(refs, refLocation{ref: d.Ref, path: location, fields: fields})
+	}
+
+	for i := range d.Children {
+		child := &d.Children[i]
+
+		childPath := fmt.Sprintf("field '%s'", child.Name)
+		if pathPrefix != "" {
+			childPath = pathPrefix + " -> " + childPath
+		}
+
+		childField := child.Name
+		if d.Type == TypeVector || d.Type == TypeList {
+			childField = "[]"
+		}
+		childFields := append(slices.Clip(fields), childField)
+
+		refs = append(refs, collectDefRefsWithPath(&child.Def, childPath, childFields)...)
+	}
+
+	return refs
+}
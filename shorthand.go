@@ -0,0 +1,73 @@
+package cuessz
+
+// ExpandShorthand rewrites shorthand types in every def to their canonical form
+// bytevector becomes a vector of uint8 and bytelist becomes a list of uint8
+func (s *Schema) ExpandShorthand() {
+	for name, def := range s.Defs {
+		expandShorthand(&def)
+		s.Defs[name] = def
+	}
+}
+
+// expandShorthand rewrites a def and its children in place
+func expandShorthand(d *Def) {
+	switch d.Type {
+	case TypeByteVector:
+		d.Type = TypeVector
+		d.Children = []Field{byteElement()}
+	case TypeByteList:
+		d.Type = TypeList
+		d.Children = []Field{byteElement()}
+	}
+
+	for i := range d.Children {
+		expandShorthand(&d.Children[i].Def)
+	}
+}
+
+// byteElement is the single uint8 element field of a canonical byte vector or list
+func byteElement() Field {
+	return Field{Name: "element", Def: Def{Type: TypeUint8}}
+}
+
+// IsBytes reports whether the def is a vector or list of uint8 (or the bytevector
+// and bytelist shorthand for them). Codecs and generators treat these as plain byte
+// arrays rather than sequences of individual uint8 elements
+func (d *Def) IsBytes() bool {
+	switch d.Type {
+	case TypeByteVector, TypeByteList:
+		return true
+	case TypeVector, TypeList:
+		return len(d.Children) == 1 && d.Children[0].Def.Type == TypeUint8
+	default:
+		return false
+	}
+}
+
+// CollapseShorthand is the inverse of ExpandShorthand: every vector or list of a
+// plain uint8 element is rewritten as bytevector or bytelist
+// It is used when writing schemas for humans, since the shorthand is far more compact
+func (s *Schema) CollapseShorthand() {
+	for name, def := range s.Defs {
+		collapseShorthand(&def)
+		s.Defs[name] = def
+	}
+}
+
+// collapseShorthand rewrites a def and its children in place
+func collapseShorthand(d *Def) {
+	if (d.Type == TypeVector || d.Type == TypeList) && d.IsBytes() &&
+		d.Children[0].Description == nil && d.Children[0].Def.Description == nil {
+		if d.Type == TypeVector {
+			d.Type = TypeByteVector
+		} else {
+			d.Type = TypeByteList
+		}
+		d.Children = nil
+		return
+	}
+
+	for i := range d.Children {
+		collapseShorthand(&d.Children[i].Def)
+	}
+}
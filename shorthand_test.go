@@ -0,0 +1,85 @@
+package cuessz
+
+import (
+	"testing"
+)
+
+func TestParseJSON_ByteShorthand(t *testing.T) {
+	schema, err := ParseJSON([]byte(`{
+		"version": "1.0.0",
+		"defs": {
+			"Root": {"type": "bytevector", "size": 32},
+			"ExtraData": {"type": "bytelist", "limit": 32},
+			"Header": {
+				"type": "container",
+				"children": [
+					{"name": "parent_root", "def": {"type": "ref", "ref": "Root"}},
+					{"name": "logs_bloom", "def": {"type": "bytevector", "size": 256}}
+				]
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	root := schema.Defs["Root"]
+	if root.Type != TypeVector || root.Size != 32 {
+		t.Errorf("expected Root to expand to vector of size 32, got %s size %d", root.Type, root.Size)
+	}
+	if len(root.Children) != 1 || root.Children[0].Name != "element" || root.Children[0].Def.Type != TypeUint8 {
+		t.Errorf("expected Root to have a single uint8 element child, got %+v", root.Children)
+	}
+	if !root.IsBytes() {
+		t.Error("expected Root to be reported as bytes")
+	}
+
+	extra := schema.Defs["ExtraData"]
+	if extra.Type != TypeList || extra.Limit != 32 || !extra.IsBytes() {
+		t.Errorf("expected ExtraData to expand to a byte list of limit 32, got %+v", extra)
+	}
+	isVar, err := extra.IsVariable(schema.Defs)
+	if err != nil || !isVar {
+		t.Errorf("expected ExtraData to be variable-size, got %v (err: %v)", isVar, err)
+	}
+
+	bloom := schema.Defs["Header"].Children[1].Def
+	if bloom.Type != TypeVector || !bloom.IsBytes() {
+		t.Errorf("expected nested bytevector to be expanded, got %+v", bloom)
+	}
+}
+
+func TestParseJSON_ByteShorthandRejectsChildren(t *testing.T) {
+	_, err := ParseJSON([]byte(`{
+		"version": "1.0.0",
+		"defs": {
+			"Root": {
+				"type": "bytevector",
+				"size": 32,
+				"children": [{"name": "element", "def": {"type": "uint16"}}]
+			}
+		}
+	}`))
+	if err == nil {
+		t.Error("expected error for bytevector with children, got nil")
+	}
+}
+
+func TestIsBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		def  Def
+		want bool
+	}{
+		{"bytevector", Def{Type: TypeByteVector, Size: 4}, true},
+		{"bytelist", Def{Type: TypeByteList, Limit: 4}, true},
+		{"vector of uint8", Def{Type: TypeVector, Size: 4, Children: []Field{byteElement()}}, true},
+		{"vector of uint16", Def{Type: TypeVector, Size: 4, Children: []Field{{Name: "element", Def: Def{Type: TypeUint16}}}}, false},
+		{"bitvector", Def{Type: TypeBitVector, Size: 8}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.def.IsBytes(); got != tt.want {
+			t.Errorf("%s: IsBytes() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
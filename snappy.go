@@ -0,0 +1,118 @@
+package cuessz
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+)
+
+// SnappyFormat is a snappy encoding of SSZ bytes
+type SnappyFormat string
+
+const (
+	// SnappyBlock is a single snappy block, as in gossip messages and spec test
+	// .ssz_snappy files
+	SnappyBlock SnappyFormat = "block"
+	// SnappyFramed is the snappy framing format, as in req/resp chunks (after their
+	// varint length prefix) and era file entries
+	SnappyFramed SnappyFormat = "framed"
+)
+
+// snappyStreamHeader starts every framed stream (the stream identifier chunk)
+var snappyStreamHeader = []byte("\xff\x06\x00\x00sNaPpY")
+
+// IsSnappyFramed reports whether data starts with the snappy framing stream identifier
+func IsSnappyFramed(data []byte) bool {
+	return bytes.HasPrefix(data, snappyStreamHeader)
+}
+
+// CompressSnappy compresses data in the given format
+func CompressSnappy(data []byte, format SnappyFormat) ([]byte, error) {
+	switch format {
+	case SnappyBlock:
+		return snappy.Encode(nil, data), nil
+	case SnappyFramed:
+		var buf bytes.Buffer
+		w := snappy.NewBufferedWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown snappy format '%s'", format)
+	}
+}
+
+// DecompressSnappy decompresses data in the given format, failing once the
+// output would exceed maxSize bytes so hostile payloads cannot exhaust memory
+func DecompressSnappy(data []byte, format SnappyFormat, maxSize uint64) ([]byte, error) {
+	switch format {
+	case SnappyBlock:
+		n, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snappy block: %w", err)
+		}
+		if uint64(n) > maxSize {
+			return nil, fmt.Errorf("snappy block decodes to %d bytes, more than the maximum of %d", n, maxSize)
+		}
+		out, err := snappy.Decode(nil, data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snappy block: %w", err)
+		}
+		return out, nil
+	case SnappyFramed:
+		// Read one byte past the maximum to tell a full payload from an oversized one
+		limit := int64(maxSize)
+		if maxSize >= 1<<63-1 {
+			limit = 1<<63 - 2
+		}
+		out, err := io.ReadAll(io.LimitReader(snappy.NewReader(bytes.NewReader(data)), limit+1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid snappy frames: %w", err)
+		}
+		if uint64(len(out)) > maxSize {
+			return nil, fmt.Errorf("snappy frames decode to more than the maximum of %d bytes", maxSize)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unknown snappy format '%s'", format)
+	}
+}
+
+// MarshalSSZSnappy serializes a value of the named def and compresses it
+func (s *Schema) MarshalSSZSnappy(def string, v Value, format SnappyFormat) ([]byte, error) {
+	data, err := s.MarshalSSZ(def, v)
+	if err != nil {
+		return nil, err
+	}
+	return CompressSnappy(data, format)
+}
+
+// UnmarshalSSZSnappy decompresses data, bounded by the max size of the named
+// def, and deserializes it
+func (s *Schema) UnmarshalSSZSnappy(def string, data []byte, format SnappyFormat) (Value, error) {
+	data, err := s.DecompressSSZ(def, data, format)
+	if err != nil {
+		return nil, err
+	}
+	return s.UnmarshalSSZ(def, data)
+}
+
+// DecompressSSZ decompresses the SSZ bytes of a value of the named def,
+// bounded by the def's max size
+func (s *Schema) DecompressSSZ(def string, data []byte, format SnappyFormat) ([]byte, error) {
+	maxSize, err := s.MaxSizeSSZ(def)
+	if err != nil {
+		return nil, err
+	}
+	out, err := DecompressSnappy(data, format, maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", def, err)
+	}
+	return out, nil
+}
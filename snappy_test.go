@@ -0,0 +1,55 @@
+package cuessz
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnappy_RoundTrip(t *testing.T) {
+	schema := sszSchema(t)
+
+	for _, format := range []SnappyFormat{SnappyBlock, SnappyFramed} {
+		data, err := schema.MarshalSSZSnappy("Mixed", mixedValue(), format)
+		if err != nil {
+			t.Fatalf("MarshalSSZSnappy(%s) failed: %v", format, err)
+		}
+		if got := IsSnappyFramed(data); got != (format == SnappyFramed) {
+			t.Errorf("IsSnappyFramed(%s) = %v", format, got)
+		}
+		value, err := schema.UnmarshalSSZSnappy("Mixed", data, format)
+		if err != nil {
+			t.Fatalf("UnmarshalSSZSnappy(%s) failed: %v", format, err)
+		}
+		if !reflect.DeepEqual(value, mixedValue()) {
+			t.Errorf("%s round trip mismatch: %#v", format, value)
+		}
+	}
+}
+
+func TestSnappy_MaxSize(t *testing.T) {
+	schema := sszSchema(t)
+
+	// Checkpoint is at most 12 bytes, so 13 must be refused before decoding
+	oversized := bytes.Repeat([]byte{0}, 13)
+	for _, format := range []SnappyFormat{SnappyBlock, SnappyFramed} {
+		data, err := CompressSnappy(oversized, format)
+		if err != nil {
+			t.Fatalf("CompressSnappy(%s) failed: %v", format, err)
+		}
+		if _, err := schema.UnmarshalSSZSnappy("Checkpoint", data, format); err == nil || !strings.Contains(err.Error(), "maximum of 12") {
+			t.Errorf("%s: expected max size error, got: %v", format, err)
+		}
+		if out, err := DecompressSnappy(data, format, 13); err != nil || !bytes.Equal(out, oversized) {
+			t.Errorf("%s: DecompressSnappy at the exact bound = %x, %v", format, out, err)
+		}
+	}
+
+	if _, err := DecompressSnappy([]byte("not snappy"), SnappyFramed, 100); err == nil {
+		t.Error("expected error for invalid framed input")
+	}
+	if _, err := DecompressSnappy(nil, "zstd", 100); err == nil || !strings.Contains(err.Error(), "unknown snappy format") {
+		t.Errorf("expected unknown format error, got: %v", err)
+	}
+}
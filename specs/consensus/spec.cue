@@ -9,46 +9,47 @@ BeaconChain: cuessz.#Schema & {
 		authors: ["gfx labs"]
 	}
 
+	// Mainnet preset values; override with presets/minimal.yaml or a devnet preset
+	constants: {
+		SLOTS_PER_EPOCH:               32
+		EPOCHS_PER_ETH1_VOTING_PERIOD: 64
+		SLOTS_PER_HISTORICAL_ROOT:     8192
+		EPOCHS_PER_HISTORICAL_VECTOR:  65536
+		EPOCHS_PER_SLASHINGS_VECTOR:   8192
+		HISTORICAL_ROOTS_LIMIT:        16777216
+		// 2**40
+		VALIDATOR_REGISTRY_LIMIT:     1099511627776
+		MAX_VALIDATORS_PER_COMMITTEE: 2048
+		MAX_PROPOSER_SLASHINGS:       16
+		MAX_ATTESTER_SLASHINGS:       2
+		MAX_ATTESTATIONS:             128
+		MAX_DEPOSITS:                 16
+		MAX_VOLUNTARY_EXITS:          16
+		SYNC_COMMITTEE_SIZE:          512
+		MAX_BYTES_PER_TRANSACTION:    1073741824
+		MAX_TRANSACTIONS_PER_PAYLOAD: 1048576
+		BYTES_PER_LOGS_BLOOM:         256
+		MAX_EXTRA_DATA_BYTES:         32
+		MAX_WITHDRAWALS_PER_PAYLOAD:  16
+		MAX_BLS_TO_EXECUTION_CHANGES: 16
+	}
+
 	defs: {
 		// ===== Basic Types =====
 
 		Root: {
-			type:   "vector"
+			type: "bytevector"
 			size: 32
-			children: [
-				{
-					name: "element"
-					def: {
-						type: "uint8"
-					}
-				},
-			]
 		}
 
 		Signature: {
-			type:   "vector"
+			type: "bytevector"
 			size: 96
-			children: [
-				{
-					name: "element"
-					def: {
-						type: "uint8"
-					}
-				},
-			]
 		}
 
 		BLSPubkey: {
-			type:   "vector"
+			type: "bytevector"
 			size: 48
-			children: [
-				{
-					name: "element"
-					def: {
-						type: "uint8"
-					}
-				},
-			]
 		}
 
 		// ===== Core Types =====
@@ -83,7 +84,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "beacon_block_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "source"
@@ -107,7 +108,7 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "aggregation_bits"
-					def: {type: "bitlist", limit: 2048}
+					def: {type: "bitlist", limit: "MAX_VALIDATORS_PER_COMMITTEE"}
 				},
 				{
 					name: "data"
@@ -142,7 +143,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "selection_proof"
-					def: {type: "vector", size: 96, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 96}
 				},
 			]
 		}
@@ -179,7 +180,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "withdrawal_credentials"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "amount"
@@ -207,16 +208,8 @@ BeaconChain: cuessz.#Schema & {
 							{
 								name: "element"
 								def: {
-									type:   "vector"
+									type: "bytevector"
 									size: 32
-									children: [
-										{
-											name: "element"
-											def: {
-												type: "uint8"
-											}
-										},
-									]
 								}
 							},
 						]
@@ -244,7 +237,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "withdrawal_credentials"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "amount"
@@ -258,7 +251,7 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "attesting_indices"
-					def: {type: "list", limit: 2048, children: [{name: "element", def: {type: "uint64"}},]}
+					def: {type: "list", limit: "MAX_VALIDATORS_PER_COMMITTEE", children: [{name: "element", def: {type: "uint64"}},]}
 				},
 				{
 					name: "data"
@@ -282,7 +275,7 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "aggregation_bits"
-					def: {type: "bitlist", limit: 2048}
+					def: {type: "bitlist", limit: "MAX_VALIDATORS_PER_COMMITTEE"}
 				},
 				{
 					name: "data"
@@ -307,11 +300,11 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "previous_version"
-					def: {type: "vector", size: 4, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 4}
 				},
 				{
 					name: "current_version"
-					def: {type: "vector", size: 4, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 4}
 				},
 				{
 					name: "epoch"
@@ -332,7 +325,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "withdrawal_credentials"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "effective_balance"
@@ -402,21 +395,13 @@ BeaconChain: cuessz.#Schema & {
 					name: "block_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
 								def: {
-									type:   "vector"
+									type: "bytevector"
 									size: 32
-									children: [
-										{
-											name: "element"
-											def: {
-												type: "uint8"
-											}
-										},
-									]
 								}
 							},
 						]
@@ -426,21 +411,13 @@ BeaconChain: cuessz.#Schema & {
 					name: "state_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
 								def: {
-									type:   "vector"
+									type: "bytevector"
 									size: 32
-									children: [
-										{
-											name: "element"
-											def: {
-												type: "uint8"
-											}
-										},
-									]
 								}
 							},
 						]
@@ -465,7 +442,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "block_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 			]
 		}
@@ -482,7 +459,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "domain"
-					def: {type: "list", limit: 8, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: 8}
 				},
 			]
 		}
@@ -627,7 +604,7 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "current_version"
-					def: {type: "vector", size: 4, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 4}
 				},
 				{
 					name: "genesis_validators_root"
@@ -651,7 +628,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "domain"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 			]
 		}
@@ -682,15 +659,15 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "block_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "parent_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "total_difficulty"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 			]
 		}
@@ -716,27 +693,27 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "graffiti"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "proposer_slashings"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
+					def: {type: "list", limit: "MAX_PROPOSER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
 				},
 				{
 					name: "attester_slashings"
-					def: {type: "list", limit: 2, children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
+					def: {type: "list", limit: "MAX_ATTESTER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
 				},
 				{
 					name: "attestations"
-					def: {type: "list", limit: 128, children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
+					def: {type: "list", limit: "MAX_ATTESTATIONS", children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
 				},
 				{
 					name: "deposits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
+					def: {type: "list", limit: "MAX_DEPOSITS", children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
 				},
 				{
 					name: "voluntary_exits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
+					def: {type: "list", limit: "MAX_VOLUNTARY_EXITS", children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
 				},
 			]
 		}
@@ -805,7 +782,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "genesis_validators_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "slot"
@@ -829,7 +806,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "block_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
@@ -845,7 +822,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "state_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
@@ -861,7 +838,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "historical_roots"
 					def: {
 						type:      "list"
-						limit: 16777216
+						limit: "HISTORICAL_ROOTS_LIMIT"
 						children: [
 							{
 								name: "element"
@@ -882,7 +859,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "eth1_data_votes"
-					def: {type: "list", limit: 2048, children: [{name: "element", def: {type: "ref", ref: "Eth1Data"}},]}
+					def: {type: "list", limit: "EPOCHS_PER_ETH1_VOTING_PERIOD * SLOTS_PER_EPOCH", children: [{name: "element", def: {type: "ref", ref: "Eth1Data"}},]}
 				},
 				{
 					name: "eth1_deposit_index"
@@ -890,17 +867,17 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "validators"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "ref", ref: "Validator"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "ref", ref: "Validator"}},]}
 				},
 				{
 					name: "balances"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint64"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "uint64"}},]}
 				},
 				{
 					name: "randao_mixes"
 					def: {
 						type:   "vector"
-						size: 65536
+						size: "EPOCHS_PER_HISTORICAL_VECTOR"
 						children: [
 							{
 								name: "element"
@@ -916,7 +893,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "slashings"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "EPOCHS_PER_SLASHINGS_VECTOR"
 						children: [
 							{
 								name: "element"
@@ -929,15 +906,15 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "previous_epoch_attestations"
-					def: {type: "list", limit: 4096, children: [{name: "element", def: {type: "ref", ref: "PendingAttestation"}},]}
+					def: {type: "list", limit: "MAX_ATTESTATIONS * SLOTS_PER_EPOCH", children: [{name: "element", def: {type: "ref", ref: "PendingAttestation"}},]}
 				},
 				{
 					name: "current_epoch_attestations"
-					def: {type: "list", limit: 4096, children: [{name: "element", def: {type: "ref", ref: "PendingAttestation"}},]}
+					def: {type: "list", limit: "MAX_ATTESTATIONS * SLOTS_PER_EPOCH", children: [{name: "element", def: {type: "ref", ref: "PendingAttestation"}},]}
 				},
 				{
 					name: "justification_bits"
-					def: {type: "vector", size: 1, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 1}
 				},
 				{
 					name: "previous_justified_checkpoint"
@@ -972,7 +949,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "pubkeys"
 					def: {
 						type:   "vector"
-						size: 512
+						size: "SYNC_COMMITTEE_SIZE"
 						children: [
 							{
 								name: "element"
@@ -999,7 +976,7 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "sync_committee_bits"
-					def: {type: "vector", size: 64, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bitvector", size: "SYNC_COMMITTEE_SIZE"}
 				},
 				{
 					name: "sync_committee_signature"
@@ -1220,27 +1197,27 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "graffiti"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "proposer_slashings"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
+					def: {type: "list", limit: "MAX_PROPOSER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
 				},
 				{
 					name: "attester_slashings"
-					def: {type: "list", limit: 2, children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
+					def: {type: "list", limit: "MAX_ATTESTER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
 				},
 				{
 					name: "attestations"
-					def: {type: "list", limit: 128, children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
+					def: {type: "list", limit: "MAX_ATTESTATIONS", children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
 				},
 				{
 					name: "deposits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
+					def: {type: "list", limit: "MAX_DEPOSITS", children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
 				},
 				{
 					name: "voluntary_exits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
+					def: {type: "list", limit: "MAX_VOLUNTARY_EXITS", children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
 				},
 				{
 					name: "sync_aggregate"
@@ -1316,7 +1293,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "genesis_validators_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "slot"
@@ -1340,7 +1317,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "block_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
@@ -1356,7 +1333,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "state_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
@@ -1372,7 +1349,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "historical_roots"
 					def: {
 						type:      "list"
-						limit: 16777216
+						limit: "HISTORICAL_ROOTS_LIMIT"
 						children: [
 							{
 								name: "element"
@@ -1393,7 +1370,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "eth1_data_votes"
-					def: {type: "list", limit: 2048, children: [{name: "element", def: {type: "ref", ref: "Eth1Data"}},]}
+					def: {type: "list", limit: "EPOCHS_PER_ETH1_VOTING_PERIOD * SLOTS_PER_EPOCH", children: [{name: "element", def: {type: "ref", ref: "Eth1Data"}},]}
 				},
 				{
 					name: "eth1_deposit_index"
@@ -1401,17 +1378,17 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "validators"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "ref", ref: "Validator"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "ref", ref: "Validator"}},]}
 				},
 				{
 					name: "balances"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint64"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "uint64"}},]}
 				},
 				{
 					name: "randao_mixes"
 					def: {
 						type:   "vector"
-						size: 65536
+						size: "EPOCHS_PER_HISTORICAL_VECTOR"
 						children: [
 							{
 								name: "element"
@@ -1427,7 +1404,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "slashings"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "EPOCHS_PER_SLASHINGS_VECTOR"
 						children: [
 							{
 								name: "element"
@@ -1440,15 +1417,15 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "previous_epoch_participation"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "VALIDATOR_REGISTRY_LIMIT"}
 				},
 				{
 					name: "current_epoch_participation"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "VALIDATOR_REGISTRY_LIMIT"}
 				},
 				{
 					name: "justification_bits"
-					def: {type: "vector", size: 1, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 1}
 				},
 				{
 					name: "previous_justified_checkpoint"
@@ -1473,7 +1450,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "inactivity_scores"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint64"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "uint64"}},]}
 				},
 				{
 					name: "current_sync_committee"
@@ -1512,7 +1489,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "aggregation_bits"
-					def: {type: "vector", size: 16, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 16}
 				},
 				{
 					name: "signature"
@@ -1617,27 +1594,27 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "parent_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "fee_recipient"
-					def: {type: "vector", size: 20, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 20}
 				},
 				{
 					name: "state_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "receipts_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "logs_bloom"
-					def: {type: "vector", size: 256, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: "BYTES_PER_LOGS_BLOOM"}
 				},
 				{
 					name: "prev_randao"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "block_number"
@@ -1657,7 +1634,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "extra_data"
-					def: {type: "list", limit: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "MAX_EXTRA_DATA_BYTES"}
 				},
 				{
 					name: "base_fee_per_gas"
@@ -1665,11 +1642,11 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "block_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "transactions_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 			]
 		}
@@ -1679,27 +1656,27 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "parent_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "fee_recipient"
-					def: {type: "vector", size: 20, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 20}
 				},
 				{
 					name: "state_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "receipts_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "logs_bloom"
-					def: {type: "vector", size: 256, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: "BYTES_PER_LOGS_BLOOM"}
 				},
 				{
 					name: "prev_randao"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "block_number"
@@ -1719,7 +1696,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "extra_data"
-					def: {type: "list", limit: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "MAX_EXTRA_DATA_BYTES"}
 				},
 				{
 					name: "base_fee_per_gas"
@@ -1727,27 +1704,19 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "block_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "transactions"
 					def: {
 						type:      "list"
-						limit: 1048576
+						limit: "MAX_TRANSACTIONS_PER_PAYLOAD"
 						children: [
 							{
 								name: "element"
 								def: {
-									type:      "list"
-									limit: 1073741824
-									children: [
-										{
-											name: "element"
-											def: {
-												type: "uint8"
-											}
-										},
-									]
+									type: "bytelist"
+									limit: "MAX_BYTES_PER_TRANSACTION"
 								}
 							},
 						]
@@ -1775,27 +1744,27 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "graffiti"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "proposer_slashings"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
+					def: {type: "list", limit: "MAX_PROPOSER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
 				},
 				{
 					name: "attester_slashings"
-					def: {type: "list", limit: 2, children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
+					def: {type: "list", limit: "MAX_ATTESTER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
 				},
 				{
 					name: "attestations"
-					def: {type: "list", limit: 128, children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
+					def: {type: "list", limit: "MAX_ATTESTATIONS", children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
 				},
 				{
 					name: "deposits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
+					def: {type: "list", limit: "MAX_DEPOSITS", children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
 				},
 				{
 					name: "voluntary_exits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
+					def: {type: "list", limit: "MAX_VOLUNTARY_EXITS", children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
 				},
 				{
 					name: "sync_aggregate"
@@ -1888,27 +1857,27 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "graffiti"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "proposer_slashings"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
+					def: {type: "list", limit: "MAX_PROPOSER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
 				},
 				{
 					name: "attester_slashings"
-					def: {type: "list", limit: 2, children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
+					def: {type: "list", limit: "MAX_ATTESTER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
 				},
 				{
 					name: "attestations"
-					def: {type: "list", limit: 128, children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
+					def: {type: "list", limit: "MAX_ATTESTATIONS", children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
 				},
 				{
 					name: "deposits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
+					def: {type: "list", limit: "MAX_DEPOSITS", children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
 				},
 				{
 					name: "voluntary_exits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
+					def: {type: "list", limit: "MAX_VOLUNTARY_EXITS", children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
 				},
 				{
 					name: "sync_aggregate"
@@ -1991,7 +1960,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "genesis_validators_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "slot"
@@ -2015,7 +1984,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "block_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
@@ -2031,7 +2000,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "state_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
@@ -2047,21 +2016,13 @@ BeaconChain: cuessz.#Schema & {
 					name: "historical_roots"
 					def: {
 						type:      "list"
-						limit: 16777216
+						limit: "HISTORICAL_ROOTS_LIMIT"
 						children: [
 							{
 								name: "element"
 								def: {
-									type:      "list"
+									type: "bytelist"
 									limit: 32
-									children: [
-										{
-											name: "element"
-											def: {
-												type: "uint8"
-											}
-										},
-									]
 								}
 							},
 						]
@@ -2076,7 +2037,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "eth1_data_votes"
-					def: {type: "list", limit: 2048, children: [{name: "element", def: {type: "ref", ref: "Eth1Data"}},]}
+					def: {type: "list", limit: "EPOCHS_PER_ETH1_VOTING_PERIOD * SLOTS_PER_EPOCH", children: [{name: "element", def: {type: "ref", ref: "Eth1Data"}},]}
 				},
 				{
 					name: "eth1_deposit_index"
@@ -2084,17 +2045,17 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "validators"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "ref", ref: "Validator"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "ref", ref: "Validator"}},]}
 				},
 				{
 					name: "balances"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint64"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "uint64"}},]}
 				},
 				{
 					name: "randao_mixes"
 					def: {
 						type:   "vector"
-						size: 65536
+						size: "EPOCHS_PER_HISTORICAL_VECTOR"
 						children: [
 							{
 								name: "element"
@@ -2110,7 +2071,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "slashings"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "EPOCHS_PER_SLASHINGS_VECTOR"
 						children: [
 							{
 								name: "element"
@@ -2123,15 +2084,15 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "previous_epoch_participation"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "VALIDATOR_REGISTRY_LIMIT"}
 				},
 				{
 					name: "current_epoch_participation"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "VALIDATOR_REGISTRY_LIMIT"}
 				},
 				{
 					name: "justification_bits"
-					def: {type: "vector", size: 1, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 1}
 				},
 				{
 					name: "previous_justified_checkpoint"
@@ -2156,7 +2117,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "inactivity_scores"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint64"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "uint64"}},]}
 				},
 				{
 					name: "current_sync_committee"
@@ -2197,7 +2158,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "address"
-					def: {type: "vector", size: 20, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 20}
 				},
 				{
 					name: "amount"
@@ -2222,7 +2183,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "to_execution_address"
-					def: {type: "vector", size: 20, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 20}
 				},
 			]
 		}
@@ -2272,27 +2233,27 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "parent_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "fee_recipient"
-					def: {type: "vector", size: 20, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 20}
 				},
 				{
 					name: "state_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "receipts_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "logs_bloom"
-					def: {type: "vector", size: 256, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: "BYTES_PER_LOGS_BLOOM"}
 				},
 				{
 					name: "prev_randao"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "block_number"
@@ -2312,7 +2273,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "extra_data"
-					def: {type: "list", limit: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "MAX_EXTRA_DATA_BYTES"}
 				},
 				{
 					name: "base_fee_per_gas"
@@ -2320,15 +2281,15 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "block_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "transactions_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "withdrawals_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 			]
 		}
@@ -2338,27 +2299,27 @@ BeaconChain: cuessz.#Schema & {
 			children: [
 				{
 					name: "parent_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "fee_recipient"
-					def: {type: "vector", size: 20, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 20}
 				},
 				{
 					name: "state_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "receipts_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "logs_bloom"
-					def: {type: "vector", size: 256, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: "BYTES_PER_LOGS_BLOOM"}
 				},
 				{
 					name: "prev_randao"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "block_number"
@@ -2378,7 +2339,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "extra_data"
-					def: {type: "list", limit: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "MAX_EXTRA_DATA_BYTES"}
 				},
 				{
 					name: "base_fee_per_gas"
@@ -2386,27 +2347,19 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "block_hash"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "transactions"
 					def: {
 						type:      "list"
-						limit: 1048576
+						limit: "MAX_TRANSACTIONS_PER_PAYLOAD"
 						children: [
 							{
 								name: "element"
 								def: {
-									type:      "list"
-									limit: 1073741824
-									children: [
-										{
-											name: "element"
-											def: {
-												type: "uint8"
-											}
-										},
-									]
+									type: "bytelist"
+									limit: "MAX_BYTES_PER_TRANSACTION"
 								}
 							},
 						]
@@ -2414,7 +2367,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "withdrawals"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "Withdrawal"}},]}
+					def: {type: "list", limit: "MAX_WITHDRAWALS_PER_PAYLOAD", children: [{name: "element", def: {type: "ref", ref: "Withdrawal"}},]}
 				},
 			]
 		}
@@ -2438,27 +2391,27 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "graffiti"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "proposer_slashings"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
+					def: {type: "list", limit: "MAX_PROPOSER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "ProposerSlashing"}},]}
 				},
 				{
 					name: "attester_slashings"
-					def: {type: "list", limit: 2, children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
+					def: {type: "list", limit: "MAX_ATTESTER_SLASHINGS", children: [{name: "element", def: {type: "ref", ref: "AttesterSlashing"}},]}
 				},
 				{
 					name: "attestations"
-					def: {type: "list", limit: 128, children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
+					def: {type: "list", limit: "MAX_ATTESTATIONS", children: [{name: "element", def: {type: "ref", ref: "Attestation"}},]}
 				},
 				{
 					name: "deposits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
+					def: {type: "list", limit: "MAX_DEPOSITS", children: [{name: "element", def: {type: "ref", ref: "Deposit"}},]}
 				},
 				{
 					name: "voluntary_exits"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
+					def: {type: "list", limit: "MAX_VOLUNTARY_EXITS", children: [{name: "element", def: {type: "ref", ref: "SignedVoluntaryExit"}},]}
 				},
 				{
 					name: "sync_aggregate"
@@ -2476,7 +2429,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "bls_to_execution_changes"
-					def: {type: "list", limit: 16, children: [{name: "element", def: {type: "ref", ref: "SignedBLSToExecutionChange"}},]}
+					def: {type: "list", limit: "MAX_BLS_TO_EXECUTION_CHANGES", children: [{name: "element", def: {type: "ref", ref: "SignedBLSToExecutionChange"}},]}
 				},
 			]
 		}
@@ -2545,7 +2498,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "genesis_validators_root"
-					def: {type: "vector", size: 32, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 32}
 				},
 				{
 					name: "slot"
@@ -2569,7 +2522,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "block_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
@@ -2585,7 +2538,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "state_roots"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "SLOTS_PER_HISTORICAL_ROOT"
 						children: [
 							{
 								name: "element"
@@ -2601,21 +2554,13 @@ BeaconChain: cuessz.#Schema & {
 					name: "historical_roots"
 					def: {
 						type:      "list"
-						limit: 16777216
+						limit: "HISTORICAL_ROOTS_LIMIT"
 						children: [
 							{
 								name: "element"
 								def: {
-									type:      "list"
+									type: "bytelist"
 									limit: 32
-									children: [
-										{
-											name: "element"
-											def: {
-												type: "uint8"
-											}
-										},
-									]
 								}
 							},
 						]
@@ -2630,7 +2575,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "eth1_data_votes"
-					def: {type: "list", limit: 2048, children: [{name: "element", def: {type: "ref", ref: "Eth1Data"}},]}
+					def: {type: "list", limit: "EPOCHS_PER_ETH1_VOTING_PERIOD * SLOTS_PER_EPOCH", children: [{name: "element", def: {type: "ref", ref: "Eth1Data"}},]}
 				},
 				{
 					name: "eth1_deposit_index"
@@ -2638,17 +2583,17 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "validators"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "ref", ref: "Validator"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "ref", ref: "Validator"}},]}
 				},
 				{
 					name: "balances"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint64"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "uint64"}},]}
 				},
 				{
 					name: "randao_mixes"
 					def: {
 						type:   "vector"
-						size: 65536
+						size: "EPOCHS_PER_HISTORICAL_VECTOR"
 						children: [
 							{
 								name: "element"
@@ -2664,7 +2609,7 @@ BeaconChain: cuessz.#Schema & {
 					name: "slashings"
 					def: {
 						type:   "vector"
-						size: 8192
+						size: "EPOCHS_PER_SLASHINGS_VECTOR"
 						children: [
 							{
 								name: "element"
@@ -2677,15 +2622,15 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "previous_epoch_participation"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "VALIDATOR_REGISTRY_LIMIT"}
 				},
 				{
 					name: "current_epoch_participation"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytelist", limit: "VALIDATOR_REGISTRY_LIMIT"}
 				},
 				{
 					name: "justification_bits"
-					def: {type: "vector", size: 1, children: [{name: "element", def: {type: "uint8"}},]}
+					def: {type: "bytevector", size: 1}
 				},
 				{
 					name: "previous_justified_checkpoint"
@@ -2710,7 +2655,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "inactivity_scores"
-					def: {type: "list", limit: 4294967296, children: [{name: "element", def: {type: "uint64"}},]}
+					def: {type: "list", limit: "VALIDATOR_REGISTRY_LIMIT", children: [{name: "element", def: {type: "uint64"}},]}
 				},
 				{
 					name: "current_sync_committee"
@@ -2743,7 +2688,7 @@ BeaconChain: cuessz.#Schema & {
 				},
 				{
 					name: "historical_summaries"
-					def: {type: "list", limit: 16777216, children: [{name: "element", def: {type: "ref", ref: "HistoricalSummary"}},]}
+					def: {type: "list", limit: "HISTORICAL_ROOTS_LIMIT", children: [{name: "element", def: {type: "ref", ref: "HistoricalSummary"}},]}
 				},
 			]
 		}
@@ -30,32 +30,16 @@ Progressive: cuessz.#Schema & {
 				{
 					name: "username"
 					def: {
-						type: "vector"
+						type: "bytevector"
 						size: 32
-						children: [
-							{
-								name: "element"
-								def: {
-									type: "uint8"
-								}
-							},
-						]
 					}
 					description: "Required: Username hash"
 				},
 				{
 					name: "email"
 					def: {
-						type: "vector"
+						type: "bytevector"
 						size: 64
-						children: [
-							{
-								name: "element"
-								def: {
-									type: "uint8"
-								}
-							},
-						]
 					}
 					description: "Optional: Email hash (added in v2)"
 				},
@@ -82,16 +66,8 @@ Progressive: cuessz.#Schema & {
 				{
 					name: "text"
 					def: {
-						type: "vector"
+						type: "bytevector"
 						size: 256
-						children: [
-							{
-								name: "element"
-								def: {
-									type: "uint8"
-								}
-							},
-						]
 					}
 					description: "Message text (at merkle position 0)"
 				},
@@ -115,16 +91,8 @@ Progressive: cuessz.#Schema & {
 				{
 					name: "text"
 					def: {
-						type: "vector"
+						type: "bytevector"
 						size: 256
-						children: [
-							{
-								name: "element"
-								def: {
-									type: "uint8"
-								}
-							},
-						]
 					}
 					description: "Message text (at merkle position 0)"
 				},
@@ -186,16 +154,8 @@ Progressive: cuessz.#Schema & {
 				{
 					name: "from"
 					def: {
-						type: "vector"
+						type: "bytevector"
 						size: 32
-						children: [
-							{
-								name: "element"
-								def: {
-									type: "uint8"
-								}
-							},
-						]
 					}
 					description: "Sender address (merkle position 0)"
 				},
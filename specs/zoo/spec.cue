@@ -14,42 +14,18 @@ Zoo: cuessz.#Schema & {
 	defs: {
 		// Basic type aliases for clarity
 		bytes32: {
-			type: "vector"
+			type: "bytevector"
 			size: 32
-			children: [
-				{
-					name: "element"
-					def: {
-						type: "uint8"
-					}
-				},
-			]
 		}
 
 		bytes48: {
-			type: "vector"
+			type: "bytevector"
 			size: 48
-			children: [
-				{
-					name: "element"
-					def: {
-						type: "uint8"
-					}
-				},
-			]
 		}
 
 		bytes96: {
-			type: "vector"
+			type: "bytevector"
 			size: 96
-			children: [
-				{
-					name: "element"
-					def: {
-						type: "uint8"
-					}
-				},
-			]
 		}
 
 		// ClockInRecords tracks when animals check in at the zoo
@@ -0,0 +1,308 @@
+package cuessz
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specForks are the consensus forks in activation order
+var specForks = []string{"phase0", "altair", "bellatrix", "capella", "deneb", "electra", "fulu"}
+
+// specCase is one ssz_static case directory of consensus-spec-tests:
+// tests/<config>/<fork>/ssz_static/<Type>/<suite>/<case>
+type specCase struct {
+	Config, Fork, Type, Dir string
+}
+
+func (c specCase) String() string {
+	return strings.Join([]string{c.Config, c.Fork, c.Type, filepath.Base(filepath.Dir(c.Dir)), filepath.Base(c.Dir)}, "/")
+}
+
+// TestConsensusSpecTests runs the ssz_static vectors of a consensus-spec-tests
+// checkout at $CUESSZ_SPEC_TESTS against the defs in specs/consensus. Each case
+// must decode, re-encode to the same bytes and match value.yaml and roots.yaml.
+// Forks the schema has no defs for, and types it does not define, are skipped
+func TestConsensusSpecTests(t *testing.T) {
+	dir := os.Getenv("CUESSZ_SPEC_TESTS")
+	if dir == "" {
+		t.Skip("CUESSZ_SPEC_TESTS is not set")
+	}
+	cases, err := findSpecCases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatalf("no ssz_static cases under %s", dir)
+	}
+
+	schemas := map[string]*Schema{"mainnet": consensusSchema(t)}
+	minimal, err := os.ReadFile("specs/consensus/presets/minimal.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	preset, err := ParsePreset(minimal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schemas["minimal"], err = schemas["mainnet"].WithPreset(preset); err != nil {
+		t.Fatal(err)
+	}
+
+	covered := map[string]bool{}
+	for _, c := range cases {
+		t.Run(c.String(), func(t *testing.T) {
+			schema, ok := schemas[c.Config]
+			if !ok {
+				t.Skipf("no preset for config %s", c.Config)
+			}
+			def, ok := specDef(schema, c.Type, c.Fork)
+			if !ok {
+				t.Skipf("schema has no def for %s in %s", c.Type, c.Fork)
+			}
+			covered[def] = true
+			if err := checkSpecCase(schema, def, c.Dir); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	var missing []string
+	for _, def := range slices.Sorted(maps.Keys(schemas["mainnet"].Defs)) {
+		if !covered[def] {
+			missing = append(missing, def)
+		}
+	}
+	if len(missing) > 0 {
+		t.Logf("defs without test vectors: %s", strings.Join(missing, ", "))
+	}
+}
+
+func TestSpecCases(t *testing.T) {
+	schema := sszSchema(t)
+	dir := t.TempDir()
+
+	// Lay out a few cases the way consensus-spec-tests does
+	for i, typ := range []string{"Checkpoint", "Mixed", "Choice"} {
+		caseDir := filepath.Join(dir, "tests", "mainnet", "phase0", "ssz_static", typ, "ssz_random", fmt.Sprintf("case_%d", i))
+		if err := os.MkdirAll(caseDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeSpecCase(t, schema, typ, caseDir, uint64(i))
+	}
+
+	cases, err := findSpecCases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range cases {
+		names = append(names, c.String())
+		if err := checkSpecCase(schema, c.Type, c.Dir); err != nil {
+			t.Errorf("%s: %v", c, err)
+		}
+	}
+	want := []string{"mainnet/phase0/Checkpoint/ssz_random/case_0", "mainnet/phase0/Choice/ssz_random/case_2", "mainnet/phase0/Mixed/ssz_random/case_1"}
+	if !slices.Equal(names, want) {
+		t.Errorf("findSpecCases = %v, expected %v", names, want)
+	}
+
+	roots := filepath.Join(cases[0].Dir, "roots.yaml")
+	if err := os.WriteFile(roots, []byte("{root: '0x"+strings.Repeat("11", 32)+"'}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSpecCase(schema, cases[0].Type, cases[0].Dir); err == nil || !strings.Contains(err.Error(), "hash_tree_root") {
+		t.Errorf("expected a hash_tree_root mismatch, got: %v", err)
+	}
+}
+
+func TestSpecDef(t *testing.T) {
+	schema := NewSchema().
+		Def("Slot", Uint64()).
+		Def("BeaconBlockBodyPhase0", Uint8()).
+		Def("BeaconBlockBodyAltair", Uint16()).
+		Def("ExecutionPayloadHeader", Uint32()).
+		Def("ExecutionPayloadHeaderCapella", Uint64()).
+		MustBuild()
+
+	tests := []struct {
+		typ, fork string
+		want      string
+	}{
+		{"BeaconBlockBody", "phase0", "BeaconBlockBodyPhase0"},
+		{"BeaconBlockBody", "bellatrix", "BeaconBlockBodyAltair"},
+		{"ExecutionPayloadHeader", "bellatrix", "ExecutionPayloadHeader"},
+		{"ExecutionPayloadHeader", "capella", "ExecutionPayloadHeaderCapella"},
+		{"Slot", "altair", "Slot"},
+		// Capella is the last fork with defs, so later forks may have changed
+		{"Slot", "deneb", ""},
+		{"Missing", "phase0", ""},
+		{"Slot", "unknown", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := specDef(schema, tt.typ, tt.fork); got != tt.want {
+			t.Errorf("specDef(%s, %s) = %q, expected %q", tt.typ, tt.fork, got, tt.want)
+		}
+	}
+}
+
+// findSpecCases walks dir for the case directories under each
+// <config>/<fork>/ssz_static directory
+func findSpecCases(dir string) ([]specCase, error) {
+	var cases []specCase
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() || entry.Name() != "ssz_static" {
+			return err
+		}
+		fork := filepath.Dir(path)
+		matches, err := filepath.Glob(filepath.Join(path, "*", "*", "*", "serialized.ssz_snappy"))
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			caseDir := filepath.Dir(match)
+			cases = append(cases, specCase{
+				Config: filepath.Base(filepath.Dir(fork)),
+				Fork:   filepath.Base(fork),
+				Type:   filepath.Base(filepath.Dir(filepath.Dir(caseDir))),
+				Dir:    caseDir,
+			})
+		}
+		return filepath.SkipDir
+	})
+	return cases, err
+}
+
+// specDef picks the def for a type in a fork: <Type><Fork>, then the same for
+// each earlier fork in specForks, then <Type>. Forks after the last one with
+// suffixed defs are not covered, as their types may have changed
+func specDef(schema *Schema, typ, fork string) (string, bool) {
+	at := slices.Index(specForks, fork)
+	if at < 0 {
+		return "", false
+	}
+	last := -1
+	for name := range schema.Defs {
+		for i, f := range specForks {
+			if i > last && strings.HasSuffix(name, forkSuffix(f)) {
+				last = i
+			}
+		}
+	}
+	if last >= 0 && at > last {
+		return "", false
+	}
+
+	for i := at; i >= 0; i-- {
+		if _, ok := schema.Defs[typ+forkSuffix(specForks[i])]; ok {
+			return typ + forkSuffix(specForks[i]), true
+		}
+	}
+	if _, ok := schema.Defs[typ]; !ok {
+		return "", false
+	}
+	return typ, true
+}
+
+func forkSuffix(fork string) string {
+	return strings.ToUpper(fork[:1]) + fork[1:]
+}
+
+// checkSpecCase checks one case directory: serialized.ssz_snappy must decode
+// and re-encode, value.yaml must encode to the same bytes and roots.yaml hold
+// the hash_tree_root
+func checkSpecCase(schema *Schema, def, dir string) error {
+	compressed, err := os.ReadFile(filepath.Join(dir, "serialized.ssz_snappy"))
+	if err != nil {
+		return err
+	}
+	data, err := schema.DecompressSSZ(def, compressed, SnappyBlock)
+	if err != nil {
+		return err
+	}
+	decoded, err := schema.UnmarshalSSZ(def, data)
+	if err != nil {
+		return err
+	}
+	again, err := schema.MarshalSSZ(def, decoded)
+	if err != nil {
+		return fmt.Errorf("%s: re-encoding the decoded value: %w", def, err)
+	}
+	if !bytes.Equal(again, data) {
+		return fmt.Errorf("%s: decoded value re-encodes to %x, expected %x", def, again, data)
+	}
+
+	valueYAML, err := os.ReadFile(filepath.Join(dir, "value.yaml"))
+	if err != nil {
+		return err
+	}
+	value, err := schema.UnmarshalValueYAML(def, valueYAML)
+	if err != nil {
+		return fmt.Errorf("value.yaml: %w", err)
+	}
+	encoded, err := schema.MarshalSSZ(def, value)
+	if err != nil {
+		return fmt.Errorf("%s: encoding value.yaml: %w", def, err)
+	}
+	if !bytes.Equal(encoded, data) {
+		return fmt.Errorf("%s: value.yaml encodes to %x, expected %x", def, encoded, data)
+	}
+
+	rootsYAML, err := os.ReadFile(filepath.Join(dir, "roots.yaml"))
+	if err != nil {
+		return err
+	}
+	var roots struct {
+		Root string `yaml:"root"`
+	}
+	if err := yaml.Unmarshal(rootsYAML, &roots); err != nil {
+		return fmt.Errorf("roots.yaml: %w", err)
+	}
+	root, err := schema.HashTreeRoot(def, decoded)
+	if err != nil {
+		return err
+	}
+	if root.String() != roots.Root {
+		return fmt.Errorf("%s: hash_tree_root is %s, roots.yaml has %s", def, root, roots.Root)
+	}
+	return nil
+}
+
+// writeSpecCase writes a random value of def as a case in the consensus-spec-tests layout
+func writeSpecCase(t *testing.T, schema *Schema, def, dir string, seed uint64) {
+	t.Helper()
+	value, err := schema.RandomValue(def, rand.NewPCG(seed, 0), RandomOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := schema.MarshalSSZSnappy(def, value, SnappyBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valueYAML, err := schema.MarshalValueYAML(def, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := schema.HashTreeRoot(def, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"serialized.ssz_snappy": data,
+		"value.yaml":            valueYAML,
+		"roots.yaml":            []byte("{root: '" + root.String() + "'}\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
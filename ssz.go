@@ -0,0 +1,646 @@
+package cuessz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// bytesPerOffset is the size of an SSZ offset (and of list length prefixes)
+const bytesPerOffset = 4
+
+// DecodeError reports malformed SSZ input: where it is and why it was rejected
+// Reason is one of the malformed-input sentinels (ErrTruncated, ErrOffset, ...),
+// so errors.Is(err, ErrOffset) works on decode errors
+type DecodeError struct {
+	// Path names the value like "BeaconState.validators[3].pubkey"
+	Path string
+	// Offset is the position in the input of the offending byte, offset or value
+	Offset uint64
+	Reason error
+	Detail string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s: %s (at byte %d)", e.Path, e.Detail, e.Offset)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Reason
+}
+
+func decodeError(path string, at uint64, reason error, format string, args ...any) error {
+	return &DecodeError{Path: path, Offset: at, Reason: reason, Detail: fmt.Sprintf(format, args...)}
+}
+
+// MarshalSSZ serializes a value of the named def (see Value) to SSZ bytes
+// The schema is expected to be valid, as returned by ParseJSON or Build
+func (s *Schema) MarshalSSZ(def string, v Value) ([]byte, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	return s.encodeSSZ(nil, d, v, def)
+}
+
+// UnmarshalSSZ deserializes SSZ bytes into a value of the named def
+// Decoding is strict: offsets must be in order and in bounds, booleans 0 or 1,
+// bitfields free of stray bits and no bytes may be left over. Malformed input
+// is reported as a *DecodeError, whose reason can be matched with errors.Is
+func (s *Schema) UnmarshalSSZ(def string, data []byte) (Value, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	return s.decodeSSZ(d, data, 0, def)
+}
+
+// SizeSSZ reports the serialized size of the named def when it is fixed,
+// and false when values of the def are variable-size
+func (s *Schema) SizeSSZ(def string) (uint64, bool, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return 0, false, err
+	}
+	size, variable, err := s.sszFixedSize(d, 0)
+	return size, !variable, err
+}
+
+// MaxSizeSSZ reports the largest serialized size of any value of the named def,
+// saturating at math.MaxUint64 for defs whose bound does not fit
+func (s *Schema) MaxSizeSSZ(def string) (uint64, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return 0, err
+	}
+	return s.sszMaxSize(d, 0)
+}
+
+// sszFixedSize returns the serialized size of a fixed-size def, or variable
+// for a def whose values vary in size
+func (s *Schema) sszFixedSize(d *Def, depth int) (size uint64, variable bool, err error) {
+	if depth > maxCycleDepth {
+		return 0, false, fmt.Errorf("%w: def nesting exceeds %d", ErrRecursiveType, maxCycleDepth)
+	}
+	d, err = s.resolveRef(d)
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64, TypeUint128, TypeUint256:
+		return uint64(uintBits(d.Type) / 8), false, nil
+	case TypeBoolean:
+		return 1, false, nil
+	case TypeBitVector:
+		return (d.Size + 7) / 8, false, nil
+	case TypeByteVector:
+		return d.Size, false, nil
+	case TypeList, TypeBitList, TypeByteList, TypeUnion:
+		return 0, true, nil
+	case TypeVector:
+		elem, variable, err := s.sszFixedSize(&d.Children[0].Def, depth+1)
+		if err != nil || variable {
+			return 0, variable, err
+		}
+		hi, size := bits.Mul64(elem, d.Size)
+		if hi != 0 {
+			return 0, false, fmt.Errorf("size of %d elements of %d bytes overflows uint64", d.Size, elem)
+		}
+		return size, false, nil
+	case TypeContainer, TypeProgressiveContainer:
+		var total uint64
+		for i := range d.Children {
+			size, variable, err := s.sszFixedSize(&d.Children[i].Def, depth+1)
+			if err != nil || variable {
+				return 0, variable, err
+			}
+			var carry uint64
+			if total, carry = bits.Add64(total, size, 0); carry != 0 {
+				return 0, false, fmt.Errorf("size of field '%s' overflows uint64", d.Children[i].Name)
+			}
+		}
+		return total, false, nil
+	default:
+		return 0, false, fmt.Errorf("invalid type '%s'", d.Type)
+	}
+}
+
+// sszMaxSize returns the largest serialized size of a def
+func (s *Schema) sszMaxSize(d *Def, depth int) (uint64, error) {
+	if depth > maxCycleDepth {
+		return 0, fmt.Errorf("%w: def nesting exceeds %d", ErrRecursiveType, maxCycleDepth)
+	}
+	d, err := s.resolveRef(d)
+	if err != nil {
+		return 0, err
+	}
+
+	switch d.Type {
+	case TypeBitList:
+		return d.Limit/8 + 1, nil
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		count := d.Size
+		if d.Type == TypeList || d.Type == TypeByteList {
+			count = d.Limit
+		}
+		// Byte arrays may be shorthand without an element field
+		elem := uint64(1)
+		if !d.IsBytes() {
+			if elem, err = s.sszElementMaxSize(&d.Children[0].Def, depth+1); err != nil {
+				return 0, err
+			}
+		}
+		return mulSaturating(count, elem), nil
+	case TypeContainer, TypeProgressiveContainer:
+		var total uint64
+		for i := range d.Children {
+			size, err := s.sszElementMaxSize(&d.Children[i].Def, depth+1)
+			if err != nil {
+				return 0, err
+			}
+			total = addSaturating(total, size)
+		}
+		return total, nil
+	case TypeUnion:
+		var largest uint64
+		for i := range d.Children {
+			size, err := s.sszMaxSize(&d.Children[i].Def, depth+1)
+			if err != nil {
+				return 0, err
+			}
+			largest = max(largest, size)
+		}
+		return addSaturating(largest, 1), nil
+	default:
+		size, _, err := s.sszFixedSize(d, depth)
+		return size, err
+	}
+}
+
+// sszElementMaxSize is the largest space an element or field takes in its parent,
+// counting the offset of a variable-size one
+func (s *Schema) sszElementMaxSize(d *Def, depth int) (uint64, error) {
+	size, err := s.sszMaxSize(d, depth)
+	if err != nil {
+		return 0, err
+	}
+	if _, variable, err := s.sszFixedSize(d, depth); err != nil || !variable {
+		return size, err
+	}
+	return addSaturating(size, bytesPerOffset), nil
+}
+
+func addSaturating(a, b uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return math.MaxUint64
+	}
+	return sum
+}
+
+func mulSaturating(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return lo
+}
+
+// encodeSSZ appends the serialization of v to buf
+func (s *Schema) encodeSSZ(buf []byte, d *Def, v Value, path string) ([]byte, error) {
+	d, err := s.resolveRef(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	fail := func(format string, args ...any) ([]byte, error) {
+		return nil, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64:
+		n, err := toUint64(v, uintBits(d.Type))
+		if err != nil {
+			return fail("%v", err)
+		}
+		return appendUint(buf, n, uintBits(d.Type)/8), nil
+	case TypeUint128, TypeUint256:
+		n, err := toBigUint(v, uintBits(d.Type))
+		if err != nil {
+			return fail("%v", err)
+		}
+		return appendBigUint(buf, n, uintBits(d.Type)/8), nil
+	case TypeBoolean:
+		b, ok := v.(bool)
+		if !ok {
+			return fail("expected bool, got %T", v)
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case TypeBitVector, TypeBitList:
+		bits, ok := v.([]bool)
+		if !ok {
+			return fail("expected []bool, got %T", v)
+		}
+		if d.Type == TypeBitVector {
+			if uint64(len(bits)) != d.Size {
+				return fail("expected %d bits, got %d", d.Size, len(bits))
+			}
+			return append(buf, packBits(bits)...), nil
+		}
+		if uint64(len(bits)) > d.Limit {
+			return fail("%d bits exceed the limit of %d", len(bits), d.Limit)
+		}
+		return append(buf, packBitlist(bits)...), nil
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		if d.IsBytes() {
+			b, ok := v.([]byte)
+			if !ok {
+				return fail("expected []byte, got %T", v)
+			}
+			if err := checkLength(d, uint64(len(b)), "bytes"); err != nil {
+				return fail("%v", err)
+			}
+			return append(buf, b...), nil
+		}
+		items, ok := v.([]any)
+		if !ok {
+			return fail("expected []any, got %T", v)
+		}
+		if err := checkLength(d, uint64(len(items)), "elements"); err != nil {
+			return fail("%v", err)
+		}
+		elem := &d.Children[0].Def
+		parts := make([]sszPart, len(items))
+		for i := range items {
+			parts[i] = sszPart{def: elem, value: items[i], path: fmt.Sprintf("%s[%d]", path, i)}
+		}
+		return s.encodeParts(buf, parts)
+	case TypeContainer, TypeProgressiveContainer:
+		fields, ok := v.(map[string]any)
+		if !ok {
+			return fail("expected map[string]any, got %T", v)
+		}
+		if err := checkFieldNames(d, fields); err != nil {
+			return fail("%v", err)
+		}
+		parts := make([]sszPart, len(d.Children))
+		for i := range d.Children {
+			child := &d.Children[i]
+			parts[i] = sszPart{def: &child.Def, value: fields[child.Name], path: path + "." + child.Name}
+		}
+		return s.encodeParts(buf, parts)
+	case TypeUnion:
+		u, ok := v.(UnionValue)
+		if !ok {
+			return fail("expected UnionValue, got %T", v)
+		}
+		if u.Selector < 0 || u.Selector >= len(d.Children) {
+			return fail("union selector %d out of range (%d options)", u.Selector, len(d.Children))
+		}
+		option := &d.Children[u.Selector]
+		return s.encodeSSZ(append(buf, byte(u.Selector)), &option.Def, u.Value, path+"."+option.Name)
+	default:
+		return fail("invalid type '%s'", d.Type)
+	}
+}
+
+// sszPart is one element or field of a composite value being encoded
+type sszPart struct {
+	def   *Def
+	value Value
+	path  string
+}
+
+// encodeParts encodes the elements or fields of a composite: fixed-size parts
+// inline and variable-size parts behind 4-byte offsets
+func (s *Schema) encodeParts(buf []byte, parts []sszPart) ([]byte, error) {
+	encoded := make([][]byte, len(parts))
+	isVariable := make([]bool, len(parts))
+	sizes := partSizes{schema: s}
+	fixedLen := 0
+	for i, part := range parts {
+		var err error
+		if _, isVariable[i], err = sizes.of(part.def); err != nil {
+			return nil, fmt.Errorf("%s: %w", part.path, err)
+		}
+		if encoded[i], err = s.encodeSSZ(nil, part.def, part.value, part.path); err != nil {
+			return nil, err
+		}
+		if isVariable[i] {
+			fixedLen += bytesPerOffset
+		} else {
+			fixedLen += len(encoded[i])
+		}
+	}
+
+	offset := fixedLen
+	for i := range parts {
+		if !isVariable[i] {
+			buf = append(buf, encoded[i]...)
+			continue
+		}
+		if uint64(offset) > 1<<32-1 {
+			return nil, fmt.Errorf("%s: offset %d does not fit in 4 bytes", parts[i].path, offset)
+		}
+		buf = appendUint(buf, uint64(offset), bytesPerOffset)
+		offset += len(encoded[i])
+	}
+	for i := range parts {
+		if isVariable[i] {
+			buf = append(buf, encoded[i]...)
+		}
+	}
+	return buf, nil
+}
+
+// partSizes is sszFixedSize for the parts of a composite, computed once for
+// the runs of parts sharing a def like the elements of a list
+type partSizes struct {
+	schema   *Schema
+	def      *Def
+	size     uint64
+	variable bool
+}
+
+func (p *partSizes) of(d *Def) (uint64, bool, error) {
+	if d != p.def {
+		size, variable, err := p.schema.sszFixedSize(d, 0)
+		if err != nil {
+			return 0, false, err
+		}
+		p.def, p.size, p.variable = d, size, variable
+	}
+	return p.size, p.variable, nil
+}
+
+// decodeSSZ decodes data, which must be exactly one value of d. at is the
+// position of data in the input, for errors
+func (s *Schema) decodeSSZ(d *Def, data []byte, at uint64, path string) (Value, error) {
+	d, err := s.resolveRef(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	size, variable, err := s.sszFixedSize(d, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := checkFixedSize(size, variable, data, at, path); err != nil {
+		return nil, err
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64:
+		var n uint64
+		for i := len(data) - 1; i >= 0; i-- {
+			n = n<<8 | uint64(data[i])
+		}
+		return n, nil
+	case TypeUint128, TypeUint256:
+		be := make([]byte, len(data))
+		for i := range data {
+			be[len(data)-1-i] = data[i]
+		}
+		return new(big.Int).SetBytes(be), nil
+	case TypeBoolean:
+		switch data[0] {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		default:
+			return nil, decodeError(path, at, ErrInvalidBoolean, "invalid boolean byte 0x%02x", data[0])
+		}
+	case TypeBitVector:
+		bits, err := unpackBits(data, d.Size)
+		if err != nil {
+			return nil, decodeError(path, at+uint64(len(data))-1, ErrInvalidBitfield, "%v", err)
+		}
+		return bits, nil
+	case TypeBitList:
+		// The limit is checked here to tell it apart from a missing delimiter bit
+		bits, err := unpackBitlist(data, math.MaxUint64)
+		if err != nil {
+			return nil, decodeError(path, at+uint64(max(len(data), 1))-1, ErrInvalidBitfield, "%v", err)
+		}
+		if uint64(len(bits)) > d.Limit {
+			return nil, decodeError(path, at, ErrLength, "bitlist has %d bits, limit is %d", len(bits), d.Limit)
+		}
+		return bits, nil
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		if d.IsBytes() {
+			if err := checkLength(d, uint64(len(data)), "bytes"); err != nil {
+				return nil, decodeError(path, at, ErrLength, "%v", err)
+			}
+			return append([]byte{}, data...), nil
+		}
+		return s.decodeSequence(d, data, at, path)
+	case TypeContainer, TypeProgressiveContainer:
+		parts := make([]*Def, len(d.Children))
+		for i := range d.Children {
+			parts[i] = &d.Children[i].Def
+		}
+		values, err := s.decodeParts(parts, data, at, func(i int) string { return path + "." + d.Children[i].Name })
+		if err != nil {
+			return nil, err
+		}
+		out := make(map[string]any, len(d.Children))
+		for i, v := range values {
+			out[d.Children[i].Name] = v
+		}
+		return out, nil
+	case TypeUnion:
+		option, err := unionOption(d, data, at, path)
+		if err != nil {
+			return nil, err
+		}
+		v, err := s.decodeSSZ(&option.Def, data[1:], at+1, path+"."+option.Name)
+		if err != nil {
+			return nil, err
+		}
+		return UnionValue{Selector: int(data[0]), Value: v}, nil
+	default:
+		return nil, fmt.Errorf("%s: invalid type '%s'", path, d.Type)
+	}
+}
+
+// checkFixedSize checks that data is exactly size bytes when the def is fixed-size
+func checkFixedSize(size uint64, variable bool, data []byte, at uint64, path string) error {
+	switch {
+	case variable || uint64(len(data)) == size:
+		return nil
+	case uint64(len(data)) < size:
+		return decodeError(path, at+uint64(len(data)), ErrTruncated, "expected %d bytes, got %d", size, len(data))
+	default:
+		return decodeError(path, at+size, ErrTrailingBytes, "expected %d bytes, got %d", size, len(data))
+	}
+}
+
+// unionOption returns the option selected by the first byte of a union
+func unionOption(d *Def, data []byte, at uint64, path string) (*Field, error) {
+	if len(data) == 0 {
+		return nil, decodeError(path, at, ErrTruncated, "union is empty, missing the selector byte")
+	}
+	if int(data[0]) >= len(d.Children) {
+		return nil, decodeError(path, at, ErrInvalidSelector, "union selector %d out of range (%d options)", data[0], len(d.Children))
+	}
+	return &d.Children[data[0]], nil
+}
+
+// decodeSequence decodes the elements of a vector or list that is not a byte array
+func (s *Schema) decodeSequence(d *Def, data []byte, at uint64, path string) (Value, error) {
+	parts, err := s.sequenceParts(d, data, at, path)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.decodeParts(parts, data, at, func(i int) string { return fmt.Sprintf("%s[%d]", path, i) })
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// sequenceParts returns the element defs of a vector or list that is not a byte array,
+// counting the elements from the data
+func (s *Schema) sequenceParts(d *Def, data []byte, at uint64, path string) ([]*Def, error) {
+	elem := &d.Children[0].Def
+	elemSize, elemVariable, err := s.sszFixedSize(elem, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// The element count comes from the data size, or the first offset for variable elements
+	var count uint64
+	switch {
+	case !elemVariable && elemSize == 0:
+		return nil, decodeError(path, at, ErrLength, "elements have size 0")
+	case !elemVariable:
+		if uint64(len(data))%elemSize != 0 {
+			return nil, decodeError(path, at, ErrLength, "%d bytes is not a multiple of the element size %d", len(data), elemSize)
+		}
+		count = uint64(len(data)) / elemSize
+	case len(data) == 0:
+		count = 0
+	default:
+		if len(data) < bytesPerOffset {
+			return nil, decodeError(path, at, ErrTruncated, "%d bytes is too short for the first offset", len(data))
+		}
+		first := uint64(binary.LittleEndian.Uint32(data))
+		if first%bytesPerOffset != 0 || first == 0 || first > uint64(len(data)) {
+			return nil, decodeError(path, at, ErrOffset, "first offset %d is not a positive multiple of %d", first, bytesPerOffset)
+		}
+		count = first / bytesPerOffset
+	}
+	if err := checkLength(d, count, "elements"); err != nil {
+		return nil, decodeError(path, at, ErrLength, "%v", err)
+	}
+
+	parts := make([]*Def, count)
+	for i := range parts {
+		parts[i] = elem
+	}
+	return parts, nil
+}
+
+// decodeParts splits data into the parts of a composite and decodes each
+func (s *Schema) decodeParts(parts []*Def, data []byte, at uint64, partPath func(int) string) ([]any, error) {
+	spans, err := s.splitParts(parts, data, at, partPath)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(parts))
+	for i, part := range parts {
+		v, err := s.decodeSSZ(part, spans[i].data, spans[i].at, partPath(i))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// sszSpan is the bytes of one part of a composite and their position in the input
+type sszSpan struct {
+	data []byte
+	at   uint64
+}
+
+// splitParts splits data into the fixed-size parts and offset-addressed
+// variable-size parts of a composite, checking the offsets
+func (s *Schema) splitParts(parts []*Def, data []byte, at uint64, partPath func(int) string) ([]sszSpan, error) {
+	type span struct{ start, end, offsetAt uint64 }
+	spans := make([]span, len(parts))
+
+	var pos uint64
+	var offsets []int
+	sizes := partSizes{schema: s}
+	for i, part := range parts {
+		size, isVariable, err := sizes.of(part)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", partPath(i), err)
+		}
+		if isVariable {
+			size = bytesPerOffset
+			offsets = append(offsets, i)
+		}
+		if pos+size > uint64(len(data)) {
+			return nil, decodeError(partPath(i), at+uint64(len(data)), ErrTruncated, "data ends at byte %d, before the fixed part", len(data))
+		}
+		if isVariable {
+			spans[i] = span{start: uint64(binary.LittleEndian.Uint32(data[pos:])), offsetAt: pos}
+		} else {
+			spans[i] = span{start: pos, end: pos + size}
+		}
+		pos += size
+	}
+
+	// Variable parts follow the fixed part back to back, in order
+	if len(offsets) == 0 && pos != uint64(len(data)) {
+		return nil, decodeError(partPath(len(parts)-1), at+pos, ErrTrailingBytes, "%d trailing bytes after the fixed part", uint64(len(data))-pos)
+	}
+	for j, i := range offsets {
+		start := spans[i].start
+		if j == 0 && start != pos {
+			return nil, decodeError(partPath(i), at+spans[i].offsetAt, ErrOffset,
+				"first offset %d does not point to the end of the fixed part at %d", start, pos)
+		}
+		end := uint64(len(data))
+		if j+1 < len(offsets) {
+			end = spans[offsets[j+1]].start
+		}
+		if start > end || end > uint64(len(data)) {
+			return nil, decodeError(partPath(i), at+spans[i].offsetAt, ErrOffset,
+				"offset %d is out of order or out of bounds (next %d, size %d)", start, end, len(data))
+		}
+		spans[i].end = end
+	}
+
+	out := make([]sszSpan, len(parts))
+	for i := range parts {
+		out[i] = sszSpan{data: data[spans[i].start:spans[i].end], at: at + spans[i].start}
+	}
+	return out, nil
+}
+
+// appendUint appends n as a little-endian integer of size bytes
+func appendUint(buf []byte, n uint64, size int) []byte {
+	for range size {
+		buf = append(buf, byte(n))
+		n >>= 8
+	}
+	return buf
+}
+
+// appendBigUint appends n as a little-endian integer of size bytes
+func appendBigUint(buf []byte, n *big.Int, size int) []byte {
+	be := n.FillBytes(make([]byte, size))
+	for i := size - 1; i >= 0; i-- {
+		buf = append(buf, be[i])
+	}
+	return buf
+}
@@ -7,10 +7,20 @@ import (
 // SSZ uses 4-byte (uint32) length prefixes for variable-length types
 #MaxSSZSize: 4294967296 // 2^32
 
+// List limits only set the merkle depth, so any uint64 will do
+#MaxSSZLimit: 18446744073709551615 // 2^64 - 1
+
 // SSZType defines all valid SSZ type names
 #SSZType: "uint8" | "uint16" | "uint32" | "uint64" | "uint128" | "uint256" | "boolean" |
 	"container" | "progressive_container" | "vector" | "list" |
-	"bitvector" | "bitlist" | "union" | "ref"
+	"bitvector" | "bitlist" | "bytevector" | "bytelist" | "union" | "ref"
+
+// ConstName is the name of a preset constant, e.g. MAX_VALIDATORS_PER_COMMITTEE
+#ConstName: =~"^[A-Z][A-Z0-9_]*$"
+
+// ConstExpr is a product of constant names and integer literals that sizes a type,
+// e.g. "MAX_VALIDATORS_PER_COMMITTEE" or "MAX_ATTESTATIONS * SLOTS_PER_EPOCH"
+#ConstExpr: =~"^([A-Z][A-Z0-9_]*|[0-9]+)( *\\* *([A-Z][A-Z0-9_]*|[0-9]+))*$"
 
 // Def represents an SSZ type definition
 #Def: {
@@ -19,16 +29,20 @@ import (
 	// Optional documentation for this type
 	description?: string
 
-	// this is the size for vectors and bitvectors (max uint32 due to 4-byte SSZ prefix)
-	if list.Contains(["vector", "bitvector"], type) {
-		size: uint & >0 & <=#MaxSSZSize
+	// this is the size for vectors, bitvectors and bytevectors (max uint32 due to 4-byte SSZ prefix)
+	if list.Contains(["vector", "bitvector", "bytevector"], type) {
+		// may also reference schema constants
+		size: (uint & >0 & <=#MaxSSZSize) | #ConstExpr
 	}
 
-	// this is the max length for lists and bitlists (max uint32 due to 4-byte SSZ prefix)
-	if list.Contains(["list", "bitlist"], type) {
-		limit: uint & >0 & <=#MaxSSZSize
+	// this is the max length for lists, bitlists and bytelists (e.g. 2^40 validators)
+	if list.Contains(["list", "bitlist", "bytelist"], type) {
+		// may also reference schema constants
+		limit: (uint & >0 & <=#MaxSSZLimit) | #ConstExpr
 	}
 
+	// bytevector and bytelist are shorthand for a vector/list of uint8 and take no children
+
 	// container, progressive_container, vector, list, union have children
 	if list.Contains(["container", "progressive_container", "union"], type) {
 		children: [...#Field]
@@ -65,9 +79,16 @@ import (
 	// Schema version for compatibility tracking
 	version: string | *"1.0.0"
 
+	// named constants that sizes and limits may reference, overridable with a preset
+	constants?: {[#ConstName]: uint}
+
 	// type definitions (the actual business)
 	defs: {[string]: #Def}
 
+	// entry-point defs (e.g. SignedBeaconBlock, BeaconState); defs not reachable
+	// from a root are reported as unused by `cuessz vet`
+	roots?: [...string]
+
 	// Optional metadata
 	metadata?: {
 		namespace?:   string
@@ -0,0 +1,241 @@
+package cuessz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sszSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema := valueSchema(t)
+	schema.Defs["Small"] = Container("Small").
+		Field("a", Uint16()).
+		Field("b", ByteList(8)).
+		Field("c", Uint8()).
+		Def()
+	schema.Defs["Nested"] = List(ByteList(4), 3)
+	schema.Defs["Counts"] = Vector(Uint32(), 2)
+	return schema
+}
+
+func TestSSZ_Encoding(t *testing.T) {
+	schema := sszSchema(t)
+
+	tests := []struct {
+		def   string
+		value Value
+		want  string
+	}{
+		// The fixed part is a(2) + offset(4) + c(1) = 7 bytes, so b starts at 7
+		{"Small", map[string]any{"a": uint64(0x0102), "b": []byte{1, 2, 3}, "c": uint64(9)}, "0201" + "07000000" + "09" + "010203"},
+		{"Nested", []any{[]byte{1}, []byte{2, 3}}, "08000000" + "09000000" + "01" + "0203"},
+		{"Nested", []any{}, ""},
+		{"Counts", []any{uint64(1), uint64(0xffffffff)}, "01000000ffffffff"},
+		{"Choice", UnionValue{Selector: 0, Value: uint64(5)}, "000500"},
+		{"Checkpoint", map[string]any{"epoch": uint64(1), "root": []byte{0xaa, 0xbb, 0xcc, 0xdd}}, "0100000000000000aabbccdd"},
+	}
+
+	for _, tt := range tests {
+		data, err := schema.MarshalSSZ(tt.def, tt.value)
+		if err != nil {
+			t.Errorf("MarshalSSZ(%s) failed: %v", tt.def, err)
+			continue
+		}
+		if got := hex.EncodeToString(data); got != tt.want {
+			t.Errorf("MarshalSSZ(%s) = %s, want %s", tt.def, got, tt.want)
+		}
+
+		back, err := schema.UnmarshalSSZ(tt.def, data)
+		if err != nil {
+			t.Errorf("UnmarshalSSZ(%s) failed: %v", tt.def, err)
+			continue
+		}
+		if !reflect.DeepEqual(back, tt.value) {
+			t.Errorf("UnmarshalSSZ(%s) = %#v, want %#v", tt.def, back, tt.value)
+		}
+	}
+}
+
+func TestSSZ_RoundTrip(t *testing.T) {
+	schema := sszSchema(t)
+
+	data, err := schema.MarshalSSZ("Mixed", mixedValue())
+	if err != nil {
+		t.Fatalf("MarshalSSZ failed: %v", err)
+	}
+	value, err := schema.UnmarshalSSZ("Mixed", data)
+	if err != nil {
+		t.Fatalf("UnmarshalSSZ failed: %v", err)
+	}
+	if !reflect.DeepEqual(value, mixedValue()) {
+		t.Errorf("round trip mismatch:\n got  %#v\n want %#v", value, mixedValue())
+	}
+
+	again, err := schema.MarshalSSZ("Mixed", value)
+	if err != nil || !bytes.Equal(again, data) {
+		t.Errorf("re-encoding differs: %x vs %x (%v)", again, data, err)
+	}
+
+	// An empty container is a fixed part of 0 bytes, not a variable one
+	empty := NewSchema().
+		Def("Empty", Container("Empty").Def()).
+		Def("Holder", Container("Holder").Field("e", Ref("Empty")).Field("b", ByteList(4)).Def()).
+		MustBuild()
+	holder := map[string]any{"e": map[string]any{}, "b": []byte{1}}
+	if data, err := empty.MarshalSSZ("Holder", holder); err != nil || hex.EncodeToString(data) != "04000000"+"01" {
+		t.Errorf("MarshalSSZ(Holder) = %x, %v; want 0400000001", data, err)
+	}
+}
+
+func TestSSZ_Size(t *testing.T) {
+	schema := sszSchema(t)
+
+	if size, fixed, err := schema.SizeSSZ("Checkpoint"); err != nil || !fixed || size != 12 {
+		t.Errorf("SizeSSZ(Checkpoint) = %d, %v, %v; want 12, true", size, fixed, err)
+	}
+	if _, fixed, err := schema.SizeSSZ("Small"); err != nil || fixed {
+		t.Errorf("SizeSSZ(Small) = %v, %v; want variable", fixed, err)
+	}
+}
+
+func TestSSZ_DecodeErrors(t *testing.T) {
+	schema := sszSchema(t)
+
+	tests := []struct {
+		def  string
+		data string
+		want string
+	}{
+		{"Checkpoint", "0100000000000000aabbcc", "Checkpoint: expected 12 bytes, got 11"},
+		{"Checkpoint", "0100000000000000aabbccddee", "expected 12 bytes, got 13"},
+		{"Small", "0201", "data ends at byte 2"},
+		{"Small", "0201080000000909", "first offset 8 does not point to the end of the fixed part at 7"},
+		{"Small", "0201070000000901020304050607080900", "Small.b: 10 bytes exceed the limit of 8"},
+		{"Nested", "0800000007000000", "out of order"},
+		{"Nested", "0300000001", "first offset 3 is not a positive multiple of 4"},
+		{"Nested", "1000000000000000000000000000000001", "4 elements exceed the limit of 3"},
+		{"Counts", "01000000", "expected 8 bytes, got 4"},
+		{"Choice", "0205", "selector 2 out of range"},
+		{"Choice", "", "missing the selector byte"},
+		{"Mixed", "02", "Mixed.bits: data ends at byte 1"},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		_, err := schema.UnmarshalSSZ(tt.def, data)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("UnmarshalSSZ(%s, %s): expected error containing %q, got: %v", tt.def, tt.data, tt.want, err)
+		}
+	}
+
+	bools := NewSchema().Def("Flag", Boolean()).MustBuild()
+	if _, err := bools.UnmarshalSSZ("Flag", []byte{2}); err == nil || !strings.Contains(err.Error(), "invalid boolean") {
+		t.Errorf("expected invalid boolean error, got: %v", err)
+	}
+}
+
+func TestSSZ_MaxSize(t *testing.T) {
+	schema := sszSchema(t)
+
+	tests := []struct {
+		def  string
+		want uint64
+	}{
+		{"Checkpoint", 12},
+		{"Small", 2 + 4 + 8 + 1},
+		{"Nested", 3 * (4 + 4)},
+		// flags + bits + justification + extra_data + balance + checkpoints + ok
+		{"Mixed", 1 + (4 + 3) + 1 + (4 + 8) + 32 + (4 + 4*12) + 1},
+		{"Choice", 1 + 12},
+	}
+	for _, tt := range tests {
+		if got, err := schema.MaxSizeSSZ(tt.def); err != nil || got != tt.want {
+			t.Errorf("MaxSizeSSZ(%s) = %d, %v; want %d", tt.def, got, err, tt.want)
+		}
+	}
+
+	// Shorthand byte arrays have no element field to size
+	short := sszSchema(t)
+	short.CollapseShorthand()
+	for _, tt := range tests {
+		if got, err := short.MaxSizeSSZ(tt.def); err != nil || got != tt.want {
+			t.Errorf("shorthand MaxSizeSSZ(%s) = %d, %v; want %d", tt.def, got, err, tt.want)
+		}
+	}
+
+	huge := NewSchema().Def("Huge", List(List(List(Uint64(), 1<<32), 1<<32), 1<<32)).MustBuild()
+	if got, err := huge.MaxSizeSSZ("Huge"); err != nil || got != math.MaxUint64 {
+		t.Errorf("MaxSizeSSZ(Huge) = %d, %v; want saturation", got, err)
+	}
+
+	// 2^29 vectors of 2^32 uint64s do not fit a fixed size in uint64, nor do
+	// two fields of 2^63 bytes
+	wide := NewSchema().
+		Def("Inner", Vector(Uint64(), 1<<32)).
+		Def("Wide", Vector(Ref("Inner"), 1<<29)).
+		Def("Half", Vector(Ref("Inner"), 1<<28)).
+		Def("Pair", Container("Pair").Field("a", Ref("Half")).Field("b", Ref("Half")).Def()).
+		MustBuild()
+	if size, fixed, err := wide.SizeSSZ("Half"); err != nil || !fixed || size != 1<<63 {
+		t.Errorf("SizeSSZ(Half) = %d, %v, %v; want 2^63", size, fixed, err)
+	}
+	for _, def := range []string{"Wide", "Pair"} {
+		if size, fixed, err := wide.SizeSSZ(def); err == nil || !strings.Contains(err.Error(), "overflows uint64") {
+			t.Errorf("SizeSSZ(%s) = %d, %v, %v; want an overflow error", def, size, fixed, err)
+		}
+	}
+}
+
+func TestSSZ_DecodeErrorDetails(t *testing.T) {
+	schema := sszSchema(t)
+	schema.Defs["Flag"] = Boolean()
+	schema.Defs["Holder"] = Container("Holder").
+		Field("a", Uint8()).
+		Field("bits", BitList(16)).
+		Def()
+
+	tests := []struct {
+		def    string
+		data   string
+		path   string
+		offset uint64
+		reason error
+	}{
+		{"Checkpoint", "0100000000000000aabbcc", "Checkpoint", 11, ErrTruncated},
+		{"Checkpoint", "0100000000000000aabbccddee", "Checkpoint", 12, ErrTrailingBytes},
+		// The offset of b sits at byte 2, after the 2 bytes of a
+		{"Small", "0201080000000909", "Small.b", 2, ErrOffset},
+		{"Small", "0201070000000901020304050607080900", "Small.b", 7, ErrLength},
+		{"Nested", "0800000007000000", "Nested[0]", 0, ErrOffset},
+		{"Nested", "1000000000000000000000000000000001", "Nested", 0, ErrLength},
+		{"Choice", "0205", "Choice", 0, ErrInvalidSelector},
+		{"Choice", "010100", "Choice.checkpoint", 3, ErrTruncated},
+		{"Flag", "02", "Flag", 0, ErrInvalidBoolean},
+		// The bitlist is reported at its last byte, which lacks the delimiter bit
+		{"Holder", "01050000000100", "Holder.bits", 6, ErrInvalidBitfield},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		_, err := schema.UnmarshalSSZ(tt.def, data)
+		var de *DecodeError
+		if !errors.As(err, &de) {
+			t.Errorf("UnmarshalSSZ(%s, %s): expected a *DecodeError, got: %v", tt.def, tt.data, err)
+			continue
+		}
+		if de.Path != tt.path || de.Offset != tt.offset || !errors.Is(err, tt.reason) {
+			t.Errorf("UnmarshalSSZ(%s, %s): got %s at byte %d (%v), expected %s at byte %d (%v)",
+				tt.def, tt.data, de.Path, de.Offset, de.Reason, tt.path, tt.offset, tt.reason)
+		}
+	}
+
+	// Offsets of nested values are relative to the start of the input
+	_, err := schema.UnmarshalSSZ("Nested", []byte{8, 0, 0, 0, 9, 0, 0, 0, 1, 2, 3, 4, 5, 6})
+	var de *DecodeError
+	if !errors.As(err, &de) || de.Path != "Nested[1]" || de.Offset != 9 || !errors.Is(err, ErrLength) {
+		t.Errorf("expected Nested[1] ErrLength at byte 9, got: %v", err)
+	}
+}
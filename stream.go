@@ -0,0 +1,428 @@
+package cuessz
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EventKind is the kind of a StreamDecoder event
+type EventKind int
+
+const (
+	// EventValue is a uint, boolean, byte array or bitfield, decoded into Event.Value
+	EventValue EventKind = iota
+	// EventStart begins a container, vector, list or union
+	EventStart
+	// EventEnd ends the composite begun by the matching EventStart
+	EventEnd
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventValue:
+		return "value"
+	case EventStart:
+		return "start"
+	case EventEnd:
+		return "end"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event is one step of a StreamDecoder walk
+type Event struct {
+	Kind EventKind
+	// Path names the value like codec errors do, e.g. "BeaconState.validators[5].pubkey"
+	Path string
+	// Def is the def of the value with refs resolved
+	Def *Def
+	// Offset and Size locate the value's bytes in the stream
+	Offset uint64
+	Size   uint64
+	// Value is set for EventValue
+	Value Value
+	// Selector is the selected option of a union, set on its EventStart
+	Selector int
+}
+
+// StreamDecoder walks SSZ data from an io.Reader in a single forward pass,
+// emitting an event per value without materializing composites. Callers can
+// Skip or Decode a composite as a whole, or Seek to a field path
+type StreamDecoder struct {
+	schema *Schema
+	r      io.Reader
+	root   *Def
+	name   string
+	size   uint64
+	pos    uint64
+	begun  bool
+	stack  []*streamFrame
+}
+
+// streamFrame is a composite being walked
+type streamFrame struct {
+	def        *Def
+	path       string
+	start, end uint64
+	// head holds bytes read before the start event: a union selector or the
+	// first offset of a sequence of variable-size elements
+	head []byte
+
+	count int
+	// sizes and variable describe the parts of a container; sequences use
+	// elemSize and elemVariable for every element
+	sizes        []uint64
+	variable     []bool
+	elemSize     uint64
+	elemVariable bool
+
+	next       int
+	inVariable bool
+	// varParts are the indexes of variable-size parts and varStarts their
+	// absolute start positions, read from the offsets
+	varParts    []int
+	varStarts   []uint64
+	varOffsetAt []uint64
+	nextVar     int
+}
+
+// NewStreamDecoder returns a StreamDecoder for size bytes of SSZ data of the
+// named def read from r. When r is also an io.Seeker, skipped bytes are seeked over
+func (s *Schema) NewStreamDecoder(def string, r io.Reader, size uint64) (*StreamDecoder, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamDecoder{schema: s, r: r, root: d, name: def, size: size}, nil
+}
+
+// Next returns the next event, or io.EOF after the root value
+func (d *StreamDecoder) Next() (*Event, error) {
+	if len(d.stack) == 0 {
+		if d.begun {
+			return nil, io.EOF
+		}
+		d.begun = true
+		return d.enter(d.root, d.name, 0, d.size)
+	}
+
+	f := d.stack[len(d.stack)-1]
+	if f.def.Type == TypeUnion {
+		if f.next == 0 {
+			f.next++
+			option := &f.def.Children[int(f.head[0])]
+			return d.enter(&option.Def, f.path+"."+option.Name, d.pos, f.end)
+		}
+		return d.leave()
+	}
+
+	// The fixed part: fixed-size parts inline, offsets for variable-size parts
+	for !f.inVariable && f.next < f.count {
+		i := f.next
+		f.next++
+		size, variable := f.partSize(i)
+		if !variable {
+			if d.pos+size > f.end {
+				return nil, decodeError(f.partPath(i), f.end, ErrTruncated, "data ends at byte %d, before the fixed part", f.end-f.start)
+			}
+			return d.enter(f.partDef(i), f.partPath(i), d.pos, d.pos+size)
+		}
+		if i == 0 && f.head != nil {
+			continue // the first offset of a sequence was read on entry
+		}
+		if d.pos+bytesPerOffset > f.end {
+			return nil, decodeError(f.partPath(i), f.end, ErrTruncated, "data ends at byte %d, before the fixed part", f.end-f.start)
+		}
+		f.varOffsetAt = append(f.varOffsetAt, d.pos)
+		buf, err := d.read(f.partPath(i), bytesPerOffset)
+		if err != nil {
+			return nil, err
+		}
+		f.varParts = append(f.varParts, i)
+		f.varStarts = append(f.varStarts, f.start+uint64(binary.LittleEndian.Uint32(buf)))
+	}
+
+	if !f.inVariable {
+		f.inVariable = true
+		if len(f.varStarts) > 0 && f.varStarts[0] != d.pos {
+			return nil, decodeError(f.partPath(f.varParts[0]), f.varOffsetAt[0], ErrOffset,
+				"first offset %d does not point to the end of the fixed part at %d", f.varStarts[0]-f.start, d.pos-f.start)
+		}
+	}
+
+	// The variable part: each part runs to the start of the next, the last to the end
+	if f.nextVar < len(f.varParts) {
+		j := f.nextVar
+		f.nextVar++
+		i := f.varParts[j]
+		start, end := f.varStarts[j], f.end
+		if j+1 < len(f.varStarts) {
+			end = f.varStarts[j+1]
+		}
+		if start > end || end > f.end {
+			return nil, decodeError(f.partPath(i), f.varOffsetAt[j], ErrOffset,
+				"offset %d is out of order or out of bounds (next %d, size %d)", start-f.start, end-f.start, f.end-f.start)
+		}
+		return d.enter(f.partDef(i), f.partPath(i), start, end)
+	}
+	return d.leave()
+}
+
+// Skip consumes the rest of the composite begun by the last EventStart,
+// including its EventEnd, without decoding it
+func (d *StreamDecoder) Skip() error {
+	if len(d.stack) == 0 {
+		return fmt.Errorf("no composite to skip")
+	}
+	f := d.stack[len(d.stack)-1]
+	if err := d.discard(f.path, f.end-d.pos); err != nil {
+		return err
+	}
+	d.stack = d.stack[:len(d.stack)-1]
+	return nil
+}
+
+// Decode decodes the composite begun by the last EventStart into a Value,
+// consuming it including its EventEnd. It must directly follow the EventStart
+func (d *StreamDecoder) Decode() (Value, error) {
+	if len(d.stack) == 0 {
+		return nil, fmt.Errorf("no composite to decode")
+	}
+	f := d.stack[len(d.stack)-1]
+	if f.next > 0 || f.inVariable {
+		return nil, fmt.Errorf("%s: Decode must directly follow the start event", f.path)
+	}
+	rest, err := d.read(f.path, f.end-d.pos)
+	if err != nil {
+		return nil, err
+	}
+	d.stack = d.stack[:len(d.stack)-1]
+	return d.schema.decodeSSZ(f.def, append(f.head, rest...), f.start, f.path)
+}
+
+// Seek advances to the value at path, relative to the root def (e.g.
+// "validators[5].pubkey"), skipping everything not on the way, and returns
+// its event. An EventStart can then be walked further, skipped or decoded
+func (d *StreamDecoder) Seek(path string) (*Event, error) {
+	target := d.name
+	if path != "" {
+		target = d.name + "." + path
+		if strings.HasPrefix(path, "[") {
+			target = d.name + path
+		}
+	}
+
+	for {
+		ev, err := d.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s: not found", target)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case ev.Path == target && ev.Kind != EventEnd:
+			return ev, nil
+		case ev.Kind == EventEnd:
+			if strings.HasPrefix(target, ev.Path+".") || strings.HasPrefix(target, ev.Path+"[") {
+				return nil, fmt.Errorf("%s: not found", target)
+			}
+		case ev.Kind == EventStart:
+			if !strings.HasPrefix(target, ev.Path+".") && !strings.HasPrefix(target, ev.Path+"[") {
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// enter begins the value of def occupying [start, end) of the stream
+func (d *StreamDecoder) enter(def *Def, path string, start, end uint64) (*Event, error) {
+	def, err := d.schema.resolveRef(def)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	size := end - start
+	if start != d.pos {
+		return nil, decodeError(path, start, ErrOffset, "expected the value at byte %d, stream is at %d", start, d.pos)
+	}
+	fixed, variable, err := d.schema.sszFixedSize(def, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	switch {
+	case !variable && size < fixed:
+		return nil, decodeError(path, end, ErrTruncated, "expected %d bytes, got %d", fixed, size)
+	case !variable && size > fixed:
+		return nil, decodeError(path, start+fixed, ErrTrailingBytes, "expected %d bytes, got %d", fixed, size)
+	}
+	maxSize, err := d.schema.sszMaxSize(def, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if size > maxSize {
+		return nil, decodeError(path, start, ErrLength, "%d bytes exceed the max size of %d", size, maxSize)
+	}
+
+	ev := &Event{Kind: EventStart, Path: path, Def: def, Offset: start, Size: size}
+	f := &streamFrame{def: def, path: path, start: start, end: end}
+
+	switch def.Type {
+	case TypeContainer, TypeProgressiveContainer:
+		f.count = len(def.Children)
+		f.sizes = make([]uint64, f.count)
+		f.variable = make([]bool, f.count)
+		for i := range def.Children {
+			if f.sizes[i], f.variable[i], err = d.schema.sszFixedSize(&def.Children[i].Def, 0); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.partPath(i), err)
+			}
+		}
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		if def.IsBytes() {
+			return d.value(ev)
+		}
+		if f.elemSize, f.elemVariable, err = d.schema.sszFixedSize(&def.Children[0].Def, 0); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		switch {
+		case !f.elemVariable && f.elemSize == 0:
+			return nil, decodeError(path, start, ErrLength, "elements have size 0")
+		case !f.elemVariable:
+			if size%f.elemSize != 0 {
+				return nil, decodeError(path, start, ErrLength, "%d bytes is not a multiple of the element size %d", size, f.elemSize)
+			}
+			f.count = int(size / f.elemSize)
+		case size > 0:
+			if size < bytesPerOffset {
+				return nil, decodeError(path, start, ErrTruncated, "%d bytes is too short for the first offset", size)
+			}
+			if f.head, err = d.read(path, bytesPerOffset); err != nil {
+				return nil, err
+			}
+			first := uint64(binary.LittleEndian.Uint32(f.head))
+			if first%bytesPerOffset != 0 || first == 0 || first > size {
+				return nil, decodeError(path, start, ErrOffset, "first offset %d is not a positive multiple of %d", first, bytesPerOffset)
+			}
+			f.count = int(first / bytesPerOffset)
+			f.varParts = append(f.varParts, 0)
+			f.varStarts = append(f.varStarts, start+first)
+			f.varOffsetAt = append(f.varOffsetAt, start)
+		}
+		if err := checkLength(def, uint64(f.count), "elements"); err != nil {
+			return nil, decodeError(path, start, ErrLength, "%v", err)
+		}
+	case TypeUnion:
+		if size == 0 {
+			return nil, decodeError(path, start, ErrTruncated, "union is empty, missing the selector byte")
+		}
+		if f.head, err = d.read(path, 1); err != nil {
+			return nil, err
+		}
+		if _, err := unionOption(def, f.head, start, path); err != nil {
+			return nil, err
+		}
+		ev.Selector = int(f.head[0])
+	default:
+		return d.value(ev)
+	}
+
+	d.stack = append(d.stack, f)
+	return ev, nil
+}
+
+// value reads and decodes a value that is not walked part by part
+func (d *StreamDecoder) value(ev *Event) (*Event, error) {
+	data, err := d.read(ev.Path, ev.Size)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Value, err = d.schema.decodeSSZ(ev.Def, data, ev.Offset, ev.Path); err != nil {
+		return nil, err
+	}
+	ev.Kind = EventValue
+	return ev, nil
+}
+
+// leave ends the innermost composite
+func (d *StreamDecoder) leave() (*Event, error) {
+	f := d.stack[len(d.stack)-1]
+	if d.pos != f.end {
+		return nil, decodeError(f.path, d.pos, ErrTrailingBytes, "%d trailing bytes after the last part", f.end-d.pos)
+	}
+	d.stack = d.stack[:len(d.stack)-1]
+	return &Event{Kind: EventEnd, Path: f.path, Def: f.def, Offset: f.start, Size: f.end - f.start}, nil
+}
+
+// read reads the next n bytes of the value at path. A stream that ends
+// before its declared size is truncated input, other read errors are wrapped
+func (d *StreamDecoder) read(path string, n uint64) ([]byte, error) {
+	buf := make([]byte, n)
+	if got, err := io.ReadFull(d.r, buf); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, decodeError(path, d.pos+uint64(got), ErrTruncated, "stream ends while reading %d bytes at %d", n, d.pos)
+		}
+		return nil, fmt.Errorf("%s: reading %d bytes at %d: %w", path, n, d.pos, err)
+	}
+	d.pos += n
+	return buf, nil
+}
+
+// discard skips the next n bytes of the value at path, truncated like read
+func (d *StreamDecoder) discard(path string, n uint64) error {
+	if n == 0 {
+		return nil
+	}
+	truncated := func(got uint64) error {
+		return decodeError(path, d.pos+got, ErrTruncated, "stream ends while skipping %d bytes at %d", n, d.pos)
+	}
+	if seeker, ok := d.r.(io.Seeker); ok {
+		// Seeking past the end succeeds, so the end is looked up to tell a truncated stream
+		at, err := seeker.Seek(0, io.SeekCurrent)
+		var end int64
+		if err == nil {
+			end, err = seeker.Seek(0, io.SeekEnd)
+		}
+		if err == nil && uint64(end-at) < n {
+			return truncated(uint64(end - at))
+		}
+		if err == nil {
+			_, err = seeker.Seek(at+int64(n), io.SeekStart)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: skipping %d bytes at %d: %w", path, n, d.pos, err)
+		}
+	} else if got, err := io.CopyN(io.Discard, d.r, int64(n)); err != nil {
+		if errors.Is(err, io.EOF) {
+			return truncated(uint64(got))
+		}
+		return fmt.Errorf("%s: skipping %d bytes at %d: %w", path, n, d.pos, err)
+	}
+	d.pos += n
+	return nil
+}
+
+func (f *streamFrame) partDef(i int) *Def {
+	if f.sizes != nil {
+		return &f.def.Children[i].Def
+	}
+	return &f.def.Children[0].Def
+}
+
+func (f *streamFrame) partPath(i int) string {
+	if f.sizes != nil {
+		return f.path + "." + f.def.Children[i].Name
+	}
+	return fmt.Sprintf("%s[%d]", f.path, i)
+}
+
+func (f *streamFrame) partSize(i int) (uint64, bool) {
+	if f.sizes != nil {
+		return f.sizes[i], f.variable[i]
+	}
+	return f.elemSize, f.elemVariable
+}
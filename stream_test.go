@@ -0,0 +1,189 @@
+package cuessz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// onlyReader hides any io.Seeker so skipping has to read through
+type onlyReader struct{ r io.Reader }
+
+func (o onlyReader) Read(p []byte) (int, error) { return o.r.Read(p) }
+
+func streamDecoder(t *testing.T, schema *Schema, def string, data []byte) *StreamDecoder {
+	t.Helper()
+	d, err := schema.NewStreamDecoder(def, onlyReader{bytes.NewReader(data)}, uint64(len(data)))
+	if err != nil {
+		t.Fatalf("NewStreamDecoder failed: %v", err)
+	}
+	return d
+}
+
+func TestStreamDecoder_Events(t *testing.T) {
+	schema := sszSchema(t)
+	data, err := schema.MarshalSSZ("Mixed", mixedValue())
+	if err != nil {
+		t.Fatalf("MarshalSSZ failed: %v", err)
+	}
+
+	d := streamDecoder(t, schema, "Mixed", data)
+	var events []string
+	for {
+		ev, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		events = append(events, fmt.Sprintf("%s %s @%d+%d", ev.Kind, ev.Path, ev.Offset, ev.Size))
+	}
+
+	// Fixed parts come first in field order, then the variable parts
+	want := []string{
+		"start Mixed @0+62",
+		"value Mixed.flags @0+1",
+		"value Mixed.justification @5+1",
+		"value Mixed.balance @10+32",
+		"value Mixed.ok @46+1",
+		"value Mixed.bits @47+1",
+		"value Mixed.extra_data @48+2",
+		"start Mixed.checkpoints @50+12",
+		"start Mixed.checkpoints[0] @50+12",
+		"value Mixed.checkpoints[0].epoch @50+8",
+		"value Mixed.checkpoints[0].root @58+4",
+		"end Mixed.checkpoints[0] @50+12",
+		"end Mixed.checkpoints @50+12",
+		"end Mixed @0+62",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("unexpected events:\n got  %s\n want %s", strings.Join(events, "\n      "), strings.Join(want, "\n      "))
+	}
+}
+
+func TestStreamDecoder_SeekSkipDecode(t *testing.T) {
+	schema := sszSchema(t)
+	data, err := schema.MarshalSSZ("Mixed", mixedValue())
+	if err != nil {
+		t.Fatalf("MarshalSSZ failed: %v", err)
+	}
+
+	d := streamDecoder(t, schema, "Mixed", data)
+	ev, err := d.Seek("checkpoints[0].root")
+	if err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	if ev.Kind != EventValue || !bytes.Equal(ev.Value.([]byte), []byte{1, 2, 3, 4}) {
+		t.Errorf("Seek = %+v", ev)
+	}
+
+	// Decode a whole composite from its start event, on a seekable reader
+	d, _ = schema.NewStreamDecoder("Mixed", bytes.NewReader(data), uint64(len(data)))
+	if ev, err = d.Seek("checkpoints"); err != nil || ev.Kind != EventStart {
+		t.Fatalf("Seek(checkpoints) = %+v, %v", ev, err)
+	}
+	value, err := d.Decode()
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(value, mixedValue()["checkpoints"]) {
+		t.Errorf("Decode = %#v", value)
+	}
+	if ev, err := d.Next(); err != nil || ev.Kind != EventEnd || ev.Path != "Mixed" {
+		t.Errorf("expected the end of Mixed after Decode, got %+v, %v", ev, err)
+	}
+
+	d = streamDecoder(t, schema, "Nested", mustHex(t, "08000000"+"09000000"+"01"+"0203"))
+	if _, err := d.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if err := d.Skip(); err != nil {
+		t.Fatalf("Skip failed: %v", err)
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF after skipping the root, got: %v", err)
+	}
+
+	d = streamDecoder(t, schema, "Mixed", data)
+	if _, err := d.Seek("checkpoints[1]"); err == nil || !strings.Contains(err.Error(), "Mixed.checkpoints[1]: not found") {
+		t.Errorf("expected not found error, got: %v", err)
+	}
+}
+
+func TestStreamDecoder_Errors(t *testing.T) {
+	schema := sszSchema(t)
+
+	tests := []struct {
+		def    string
+		data   string
+		want   string
+		reason error
+	}{
+		{"Checkpoint", "0100000000000000aabbcc", "Checkpoint: expected 12 bytes, got 11", ErrTruncated},
+		{"Small", "0201080000000909", "first offset 8 does not point to the end of the fixed part at 7", ErrOffset},
+		{"Nested", "0800000007000000", "out of order", ErrOffset},
+		{"Nested", "0300000001", "first offset 3 is not a positive multiple of 4", ErrOffset},
+		{"Choice", "0205", "selector 2 out of range", ErrInvalidSelector},
+		{"Small", "0201070000000901020304050607080900", "Small: 17 bytes exceed the max size of 15", ErrLength},
+	}
+	for _, tt := range tests {
+		d := streamDecoder(t, schema, tt.def, mustHex(t, tt.data))
+		var err error
+		for err == nil {
+			_, err = d.Next()
+		}
+		if err == io.EOF || !strings.Contains(err.Error(), tt.want) || !errors.Is(err, tt.reason) {
+			t.Errorf("%s %s: expected %v error containing %q, got: %v", tt.def, tt.data, tt.reason, tt.want, err)
+		}
+	}
+
+	// A stream shorter than its declared size is truncated where it ends
+	d, err := schema.NewStreamDecoder("Checkpoint", onlyReader{bytes.NewReader(mustHex(t, "0100000000000000aabbcc"))}, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for err == nil {
+		_, err = d.Next()
+	}
+	var de *DecodeError
+	if !errors.Is(err, ErrTruncated) || !errors.As(err, &de) || de.Offset != 11 {
+		t.Errorf("expected truncation at byte 11, got: %v", err)
+	}
+
+	// Skipping past the end is truncated too, whether it seeks or reads through
+	holder := NewSchema().Add(Container("Holder").Field("a", Uint8()).Field("v", Vector(Uint64(), 4))).MustBuild()
+	short := make([]byte, 10)
+	for _, r := range []io.Reader{bytes.NewReader(short), onlyReader{bytes.NewReader(short)}} {
+		d, err := holder.NewStreamDecoder("Holder", r, 33)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Seek("v"); err != nil {
+			t.Fatalf("Seek failed: %v", err)
+		}
+		err = d.Skip()
+		if !errors.Is(err, ErrTruncated) || !errors.As(err, &de) || de.Offset != 10 || de.Path != "Holder.v" {
+			t.Errorf("%T: expected Holder.v truncated at byte 10, got: %v", r, err)
+		}
+	}
+
+	empty := NewSchema().Def("Empty", Container("Empty").Def()).Def("Empties", List(Ref("Empty"), 4)).MustBuild()
+	if _, err := streamDecoder(t, empty, "Empties", nil).Next(); !errors.Is(err, ErrLength) {
+		t.Errorf("expected a length error for elements of size 0, got: %v", err)
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,38 @@
+// Package gostructs holds fastssz-tagged structs for the FromGoPackage tests
+package gostructs
+
+type Checkpoint struct {
+	Epoch uint64   `json:"epoch"`
+	Root  [32]byte `json:"root" ssz-size:"32"`
+}
+
+type AttestationData struct {
+	Slot            uint64
+	Index           uint64
+	BeaconBlockRoot []byte `ssz-size:"32"`
+	Source          *Checkpoint
+	Target          *Checkpoint
+}
+
+type Attestation struct {
+	AggregationBits []byte `ssz:"bitlist" ssz-max:"2048"`
+	Data            *AttestationData
+	Signature       []byte `ssz-size:"96"`
+}
+
+type HistoricalBatch struct {
+	BlockRoots [][]byte   `ssz-size:"8192,32"`
+	StateRoots [][32]byte `ssz-size:"8192"`
+}
+
+type SyncAggregate struct {
+	SyncCommitteeBits      []byte `ssz:"bitvector" ssz-size:"64"`
+	SyncCommitteeSignature [96]byte
+}
+
+type notExported struct {
+	Value uint64
+}
+
+// Alias is not a struct and is skipped
+type Alias uint64
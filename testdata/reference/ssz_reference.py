@@ -0,0 +1,205 @@
+#!/usr/bin/env python3
+"""A small SSZ reference, written from the consensus specs independently of the Go
+code, for the fixtures checked by TestReferenceFixtures.
+
+It reads fixtures in the format written by TestExportFixtures, serializes and hashes
+their value from the schema and value alone, and rewrites "serialized" and "root"
+with its own results:
+
+    CUESSZ_EXPORT_FIXTURES=/tmp/fixtures go test -run TestExportFixtures
+    python3 testdata/reference/ssz_reference.py /tmp/fixtures/*.json
+
+Values are in the MarshalValueJSON form: uints are decimal strings, byte arrays
+and bitfields are the 0x hex of their SSZ bytes and unions are {selector, value}.
+"""
+
+import hashlib
+import json
+import sys
+
+BYTES_PER_CHUNK = 32
+ZERO_HASHES = [bytes(32)]
+for _ in range(64):
+    ZERO_HASHES.append(hashlib.sha256(ZERO_HASHES[-1] * 2).digest())
+
+UINT_SIZES = {"uint8": 1, "uint16": 2, "uint32": 4, "uint64": 8, "uint128": 16, "uint256": 32}
+
+
+def h(a, b):
+    return hashlib.sha256(a + b).digest()
+
+
+class Schema:
+    def __init__(self, doc):
+        self.defs = doc["defs"]
+
+    def resolve(self, d):
+        while d["type"] == "ref":
+            d = self.defs[d["ref"]]
+        return d
+
+    def elem(self, d):
+        return self.resolve(d["children"][0]["def"])
+
+    def is_bytes(self, d):
+        if d["type"] in ("bytevector", "bytelist"):
+            return True
+        return d["type"] in ("vector", "list") and d["children"][0]["def"]["type"] == "uint8"
+
+    def is_variable(self, d):
+        d = self.resolve(d)
+        t = d["type"]
+        if t in ("list", "bitlist", "bytelist", "union"):
+            return True
+        if t == "vector":
+            return self.is_variable(d["children"][0]["def"])
+        if t in ("container", "progressive_container"):
+            return any(self.is_variable(c["def"]) for c in d["children"])
+        return False
+
+    # Serialization
+
+    def serialize(self, d, v):
+        d = self.resolve(d)
+        t = d["type"]
+        if t in UINT_SIZES:
+            return int(v).to_bytes(UINT_SIZES[t], "little")
+        if t == "boolean":
+            return b"\x01" if v else b"\x00"
+        if t in ("bitvector", "bitlist") or self.is_bytes(d):
+            return bytes.fromhex(v[2:])
+        if t in ("vector", "list"):
+            return self.serialize_parts([d["children"][0]["def"]] * len(v), v)
+        if t in ("container", "progressive_container"):
+            return self.serialize_parts([c["def"] for c in d["children"]], [v[c["name"]] for c in d["children"]])
+        if t == "union":
+            selector = int(v["selector"])
+            return bytes([selector]) + self.serialize(d["children"][selector]["def"], v["value"])
+        raise ValueError("unknown type " + t)
+
+    def serialize_parts(self, defs, values):
+        fixed, variable = [], []
+        for d, v in zip(defs, values):
+            data = self.serialize(d, v)
+            if self.is_variable(d):
+                fixed.append(None)
+                variable.append(data)
+            else:
+                fixed.append(data)
+                variable.append(b"")
+        offset = sum(4 if part is None else len(part) for part in fixed)
+        out = b""
+        for part, tail in zip(fixed, variable):
+            if part is None:
+                out += offset.to_bytes(4, "little")
+                offset += len(tail)
+            else:
+                out += part
+        return out + b"".join(variable)
+
+    # Merkleization
+
+    def root(self, d, v):
+        d = self.resolve(d)
+        t = d["type"]
+        if t in UINT_SIZES or t == "boolean":
+            return pack(self.serialize(d, v))[0]
+        if t == "bitvector":
+            return merkleize(pack(bytes.fromhex(v[2:])), (d["size"] + 255) // 256)
+        if t == "bitlist":
+            bits = bitlist_bits(bytes.fromhex(v[2:]))
+            return mix_in(merkleize(pack(bits_bytes(bits)), (d["limit"] + 255) // 256), len(bits))
+        if self.is_bytes(d):
+            data = bytes.fromhex(v[2:])
+            bound = d.get("size") or d.get("limit")
+            chunks = merkleize(pack(data), (bound + 31) // 32)
+            return chunks if t in ("vector", "bytevector") else mix_in(chunks, len(data))
+        if t in ("vector", "list"):
+            elem = self.elem(d)
+            bound = d["size"] if t == "vector" else d["limit"]
+            if elem["type"] in UINT_SIZES or elem["type"] == "boolean":
+                size = UINT_SIZES.get(elem["type"], 1)
+                data = b"".join(self.serialize(elem, x) for x in v)
+                root = merkleize(pack(data), (bound * size + 31) // 32)
+            else:
+                root = merkleize([self.root(elem, x) for x in v], bound)
+            return root if t == "vector" else mix_in(root, len(v))
+        if t == "container":
+            roots = [self.root(c["def"], v[c["name"]]) for c in d["children"]]
+            return merkleize(roots, len(roots))
+        if t == "progressive_container":
+            # Inactive positions are zero leaves; the active_fields bitvector is mixed in
+            fields = iter(d["children"])
+            roots = []
+            for active in d["active_fields"]:
+                if active:
+                    c = next(fields)
+                    roots.append(self.root(c["def"], v[c["name"]]))
+                else:
+                    roots.append(bytes(32))
+            bits = bits_bytes([a == 1 for a in d["active_fields"]])
+            return h(merkleize_progressive(roots), bits.ljust(32, b"\x00"))
+        if t == "union":
+            selector = int(v["selector"])
+            return mix_in(self.root(d["children"][selector]["def"], v["value"]), selector)
+        raise ValueError("unknown type " + t)
+
+
+def pack(data):
+    if not data:
+        return [bytes(32)]
+    data = data.ljust((len(data) + 31) // 32 * 32, b"\x00")
+    return [data[i:i + 32] for i in range(0, len(data), 32)]
+
+
+def merkleize(chunks, limit):
+    depth = max(limit - 1, 0).bit_length()
+    layer = list(chunks)
+    if not layer:
+        return ZERO_HASHES[depth]
+    for level in range(depth):
+        if len(layer) % 2:
+            layer.append(ZERO_HASHES[level])
+        layer = [h(layer[i], layer[i + 1]) for i in range(0, len(layer), 2)]
+    return layer[0]
+
+
+def merkleize_progressive(chunks, num_leaves=1):
+    if not chunks:
+        return bytes(32)
+    return h(merkleize_progressive(chunks[num_leaves:], num_leaves * 4), merkleize(chunks[:num_leaves], num_leaves))
+
+
+def mix_in(root, n):
+    return h(root, n.to_bytes(32, "little"))
+
+
+def bitlist_bits(data):
+    last = data[-1]
+    length = (len(data) - 1) * 8 + last.bit_length() - 1
+    return [bool(data[i // 8] >> (i % 8) & 1) for i in range(length)]
+
+
+def bits_bytes(bits):
+    out = bytearray((len(bits) + 7) // 8)
+    for i, bit in enumerate(bits):
+        if bit:
+            out[i // 8] |= 1 << (i % 8)
+    return bytes(out)
+
+
+def main(paths):
+    for path in paths:
+        with open(path) as f:
+            fixture = json.load(f)
+        schema = Schema(fixture["schema"])
+        d = {"type": "ref", "ref": fixture["type"]}
+        fixture["serialized"] = "0x" + schema.serialize(d, fixture["value"]).hex()
+        fixture["root"] = "0x" + schema.root(d, fixture["value"]).hex()
+        with open(path, "w") as f:
+            json.dump(fixture, f, indent=2)
+            f.write("\n")
+
+
+if __name__ == "__main__":
+    main(sys.argv[1:])
@@ -0,0 +1,62 @@
+package cuessz
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// TopoSort returns the names of all defs in dependency order, leaves first:
+// every def comes after the defs it references, so generators can emit
+// declarations before their use
+// The order is stable: defs are visited by name and refs in field order
+// Refs to undefined defs are skipped here; Validate reports them
+func (s *Schema) TopoSort() ([]string, error) {
+	order := make([]string, 0, len(s.Defs))
+	visited := make(map[string]bool)
+
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		if cycle := s.topoVisit(name, visited, make(map[string]bool), &order, 0); cycle != nil {
+			return nil, fmt.Errorf("%w: %s", ErrRecursiveType, strings.Join(cycle, " -> "))
+		}
+	}
+
+	return order, nil
+}
+
+// topoVisit appends defName to order after everything it references, returning
+// the cycle path if one is found; it follows detectCycleInCUE on Go values
+func (s *Schema) topoVisit(defName string, visited, path map[string]bool, order *[]string, depth int) []string {
+	// Depth protection
+	if depth > maxCycleDepth {
+		return []string{fmt.Sprintf("<max-depth-%d-exceeded>", maxCycleDepth), defName}
+	}
+
+	// Check if we're in a cycle
+	if path[defName] {
+		return []string{defName}
+	}
+	if visited[defName] {
+		return nil
+	}
+
+	def, ok := s.Defs[defName]
+	if !ok {
+		// Ref not found - this is caught by ref validation, there is nothing to order
+		return nil
+	}
+
+	visited[defName] = true
+	path[defName] = true
+	defer delete(path, defName)
+
+	for _, refLoc := range collectDefRefs(&def) {
+		if cycle := s.topoVisit(refLoc.ref, visited, path, order, depth+1); cycle != nil {
+			return append([]string{defName}, cycle...)
+		}
+	}
+
+	*order = append(*order, defName)
+	return nil
+}
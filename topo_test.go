@@ -0,0 +1,62 @@
+package cuessz
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTopoSort(t *testing.T) {
+	schema, err := ParseJSON(graphSchema)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	order, err := schema.TopoSort()
+	if err != nil {
+		t.Fatalf("TopoSort failed: %v", err)
+	}
+
+	want := []string{"Root", "Checkpoint", "State", "Unrelated"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("expected order %v, got %v", want, order)
+	}
+
+	// Every def must come after the defs it references
+	for i, name := range order {
+		def := schema.Defs[name]
+		for _, refLoc := range collectDefRefs(&def) {
+			if j := slices.Index(order, refLoc.ref); j > i {
+				t.Errorf("%s (at %d) comes before its dependency %s (at %d)", name, i, refLoc.ref, j)
+			}
+		}
+	}
+
+	// The order is stable across calls
+	again, err := schema.TopoSort()
+	if err != nil {
+		t.Fatalf("TopoSort failed: %v", err)
+	}
+	if !reflect.DeepEqual(order, again) {
+		t.Errorf("expected stable order, got %v then %v", order, again)
+	}
+}
+
+func TestTopoSort_Cycle(t *testing.T) {
+	schema := &Schema{
+		Defs: map[string]Def{
+			"TypeA": {Type: TypeContainer, Children: []Field{{Name: "b", Def: Def{Type: TypeRef, Ref: "TypeB"}}}},
+			"TypeB": {Type: TypeList, Limit: 4, Children: []Field{{Name: "element", Def: Def{Type: TypeRef, Ref: "TypeA"}}}},
+		},
+	}
+
+	_, err := schema.TopoSort()
+	if !errors.Is(err, ErrRecursiveType) {
+		t.Fatalf("expected ErrRecursiveType, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "TypeA -> TypeB -> TypeA") {
+		t.Errorf("expected cycle path in error, got: %v", err)
+	}
+}
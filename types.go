@@ -9,6 +9,39 @@ import (
 var (
 	// ErrRecursiveType indicates a recursive type reference was detected
 	ErrRecursiveType = errors.New("recursive type reference detected")
+
+	// ErrInvalidDef indicates a def is structurally invalid (missing size, wrong child count, ...)
+	ErrInvalidDef = errors.New("invalid def")
+
+	// ErrUnknownConstant indicates a size or limit references an undeclared constant
+	ErrUnknownConstant = errors.New("unknown constant")
+)
+
+// Sentinel errors for malformed SSZ input, the reasons of a *DecodeError
+var (
+	// ErrTruncated indicates the data ends before a value or fixed part does
+	ErrTruncated = errors.New("truncated data")
+
+	// ErrTrailingBytes indicates bytes left over after a fixed-size value
+	ErrTrailingBytes = errors.New("trailing bytes")
+
+	// ErrOffset indicates an offset that is out of order, out of bounds, or not
+	// at the end of the fixed part
+	ErrOffset = errors.New("invalid offset")
+
+	// ErrLength indicates a length that does not fit the def: over a limit, not the
+	// size of a vector, or not a multiple of the element size
+	ErrLength = errors.New("invalid length")
+
+	// ErrInvalidBoolean indicates a boolean byte other than 0 or 1
+	ErrInvalidBoolean = errors.New("invalid boolean")
+
+	// ErrInvalidBitfield indicates a bitlist without its delimiter bit or a bitvector
+	// with padding bits set
+	ErrInvalidBitfield = errors.New("invalid bitfield")
+
+	// ErrInvalidSelector indicates a union selector with no matching option
+	ErrInvalidSelector = errors.New("invalid union selector")
 )
 
 // TypeName represents the type discriminator for SSZ types
@@ -33,6 +66,11 @@ const (
 	TypeBitVector TypeName = "bitvector"
 	TypeBitList   TypeName = "bitlist"
 
+	// TypeByteVector and TypeByteList are shorthand for a vector or list of uint8.
+	// They are expanded to the canonical form when a schema is parsed.
+	TypeByteVector TypeName = "bytevector"
+	TypeByteList   TypeName = "bytelist"
+
 	TypeUnion TypeName = "union"
 
 	// TypeRef is a special type that references another type in the schema
@@ -54,7 +92,7 @@ func (t TypeName) IsSometimesVariable() bool {
 
 func (t TypeName) IsAlwaysVariable() bool {
 	switch t {
-	case TypeList, TypeBitList:
+	case TypeList, TypeBitList, TypeByteList:
 		return true
 	default:
 		return false
@@ -63,7 +101,7 @@ func (t TypeName) IsAlwaysVariable() bool {
 
 func (t TypeName) IsAlwaysFixed() bool {
 	switch t {
-	case TypeUint8, TypeUint16, TypeUint32, TypeUint64, TypeUint128, TypeUint256, TypeBoolean, TypeBitVector:
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64, TypeUint128, TypeUint256, TypeBoolean, TypeBitVector, TypeByteVector:
 		return true
 	default:
 		return false
@@ -79,6 +117,12 @@ type Def struct {
 	Size  uint64 `json:"size,omitempty" yaml:"size,omitempty"`
 	Limit uint64 `json:"limit,omitempty" yaml:"limit,omitempty"`
 
+	// SizeExpr and LimitExpr hold the constant expression (e.g. "MAX_VALIDATORS_PER_COMMITTEE")
+	// that Size and Limit were resolved from, if any. They are written in place of
+	// size and limit by the JSON and YAML marshalers
+	SizeExpr  string `json:"-" yaml:"-"`
+	LimitExpr string `json:"-" yaml:"-"`
+
 	Ref      string  `json:"ref,omitempty" yaml:"ref,omitempty"`
 	Children []Field `json:"children,omitempty" yaml:"children,omitempty"`
 
@@ -92,6 +136,22 @@ type Field struct {
 	Description *string `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
+// Clone returns a deep copy of the def and its children
+func (d Def) Clone() Def {
+	if d.Children != nil {
+		children := make([]Field, len(d.Children))
+		for i, child := range d.Children {
+			children[i] = child
+			children[i].Def = child.Def.Clone()
+		}
+		d.Children = children
+	}
+	if d.ActiveFields != nil {
+		d.ActiveFields = append([]int(nil), d.ActiveFields...)
+	}
+	return d
+}
+
 // IsVariable determines if a def is variable-size
 func (d *Def) IsVariable(refs map[string]Def) (bool, error) {
 	const maxIterations = 1000 // Sanity check to prevent infinite recursion
@@ -105,7 +165,7 @@ func isVariable(d *Def, refs map[string]Def, iterations, maxIterations int) (boo
 	}
 
 	switch d.Type {
-	case TypeList, TypeBitList, TypeUnion:
+	case TypeList, TypeBitList, TypeByteList, TypeUnion:
 		return true, nil
 	case TypeProgressiveContainer, TypeContainer, TypeVector, TypeBitVector:
 		// Progressive/regular containers and vectors are variable-size only if they contain variable-size children
@@ -132,14 +192,16 @@ func isVariable(d *Def, refs map[string]Def, iterations, maxIterations int) (boo
 	return false, nil
 }
 
-// Note: Validation is now handled by CUE schema in ssz_schema.cue
-// The IsValid method has been removed to avoid redundant validation
+// Note: Validation of parsed schemas is handled by the CUE schema in ssz_schema.cue,
+// and Schema.Validate repeats the checks on the Go side for schemas built in Go
 
 // Schema represents a collection of named SSZ type definitions
 type Schema struct {
-	Version  string         `json:"version" yaml:"version"`
-	Defs     map[string]Def `json:"defs" yaml:"defs"`
-	Metadata *Metadata      `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Version   string            `json:"version" yaml:"version"`
+	Constants map[string]uint64 `json:"constants,omitempty" yaml:"constants,omitempty"`
+	Defs      map[string]Def    `json:"defs" yaml:"defs"`
+	Roots     []string          `json:"roots,omitempty" yaml:"roots,omitempty"`
+	Metadata  *Metadata         `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
 // Metadata contains optional schema metadata
@@ -150,10 +212,54 @@ type Metadata struct {
 }
 
 // Validate validates all defs in the schema
+// It runs the same structural, ref and cycle checks as ParseJSON does through CUE,
+// so it is authoritative for schemas built or modified in Go
 func (s *Schema) Validate() error {
 	if s.Defs == nil {
 		return fmt.Errorf("schema defs cannot be nil")
 	}
 
+	if err := s.checkDefs(); err != nil {
+		return err
+	}
+
+	if err := s.checkRefs(); err != nil {
+		return err
+	}
+
+	if err := s.checkCycles(); err != nil {
+		return err
+	}
+
+	if err := s.checkConstants(); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// Clone returns a deep copy of the schema
+func (s *Schema) Clone() *Schema {
+	out := &Schema{
+		Version: s.Version,
+		Defs:    make(map[string]Def, len(s.Defs)),
+	}
+	if s.Constants != nil {
+		out.Constants = make(map[string]uint64, len(s.Constants))
+		for name, v := range s.Constants {
+			out.Constants[name] = v
+		}
+	}
+	for name, def := range s.Defs {
+		out.Defs[name] = def.Clone()
+	}
+	if s.Roots != nil {
+		out.Roots = append([]string(nil), s.Roots...)
+	}
+	if s.Metadata != nil {
+		md := *s.Metadata
+		md.Authors = append([]string(nil), s.Metadata.Authors...)
+		out.Metadata = &md
+	}
+	return out
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"cuelang.org/go/cue"
@@ -27,55 +28,70 @@ var sszSchemaCUE string
 // ParseJSON parses and validates a JSON schema against the CUE schema definition
 // Consumers should convert YAML to JSON before calling this function
 func ParseJSON(data []byte) (*Schema, error) {
+	schema, _, err := parseJSON(data)
+	return schema, err
+}
+
+// parseJSON implements ParseJSON, additionally returning the validated CUE value
+// for checks that walk the CUE representation
+func parseJSON(data []byte) (*Schema, cue.Value, error) {
 	ctx := cuecontext.New()
 
 	// Load the CUE schema
 	schemaValue := ctx.CompileString(sszSchemaCUE)
 	if schemaValue.Err() != nil {
-		return nil, fmt.Errorf("failed to compile CUE schema: %w", schemaValue.Err())
+		return nil, cue.Value{}, fmt.Errorf("failed to compile CUE schema: %w", schemaValue.Err())
 	}
 
 	// Parse the JSON data into CUE
 	dataValue := ctx.CompileBytes(data)
 	if dataValue.Err() != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", dataValue.Err())
+		return nil, cue.Value{}, fmt.Errorf("failed to parse JSON: %w", dataValue.Err())
 	}
 
 	// Validate against #Schema
 	schemaType := schemaValue.LookupPath(cue.ParsePath("#Schema"))
 	if schemaType.Err() != nil {
-		return nil, fmt.Errorf("failed to find #Schema in CUE schema: %w", schemaType.Err())
+		return nil, cue.Value{}, fmt.Errorf("failed to find #Schema in CUE schema: %w", schemaType.Err())
 	}
 
 	// Unify the data with the schema type
 	unified := schemaType.Unify(dataValue)
 	if err := unified.Validate(cue.Concrete(true)); err != nil {
 		// Improve error messages for common cases (pass dataValue to get original values)
-		return nil, fmt.Errorf("%w: %w", ErrCUEValidation, EnhanceCUEError(err, dataValue))
+		return nil, cue.Value{}, fmt.Errorf("%w: %w", ErrCUEValidation, EnhanceCUEError(err, dataValue))
 	}
 
 	// Check for cycles using CUE API before parsing to Go
 	if err := checkCyclesWithCUE(unified); err != nil {
-		return nil, err
+		return nil, cue.Value{}, err
 	}
 
 	// Check that all refs point to valid top-level defs
 	if err := checkRefsWithCUE(unified); err != nil {
-		return nil, err
+		return nil, cue.Value{}, err
 	}
 
 	// Parse into Go struct
 	var schema Schema
 	if err := json.Unmarshal(data, &schema); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal into Go struct: %w", err)
+		return nil, cue.Value{}, fmt.Errorf("failed to unmarshal into Go struct: %w", err)
+	}
+
+	// Expand shorthand types (bytevector, bytelist) to their canonical form
+	schema.ExpandShorthand()
+
+	// Resolve constant expressions in sizes and limits
+	if err := schema.ResolveConstants(); err != nil {
+		return nil, cue.Value{}, fmt.Errorf("failed to resolve constants: %w", err)
 	}
 
 	// Also run Go-side validation for additional checks (field validation, etc.)
 	if err := schema.Validate(); err != nil {
-		return nil, fmt.Errorf("Go validation failed: %w", err)
+		return nil, cue.Value{}, fmt.Errorf("Go validation failed: %w", err)
 	}
 
-	return &schema, nil
+	return &schema, unified, nil
 }
 
 // EnhanceCUEError improves CUE error messages for common validation failures using CUE's error API
@@ -125,7 +141,7 @@ func EnhanceCUEError(err error, schemaValue cue.Value) error {
 				if typeValue.Exists() {
 					// First try to get the value as a string (works for JSON and simple CUE values)
 					if typeStr, err := typeValue.String(); err == nil && typeStr != "" && !strings.Contains(typeStr, "_|_") {
-						return fmt.Errorf("def '%s' has invalid type '%s' - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, union, ref",
+						return fmt.Errorf("def '%s' has invalid type '%s' - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, bytevector, bytelist, union, ref",
 							defName, typeStr)
 					}
 
@@ -136,7 +152,7 @@ func EnhanceCUEError(err error, schemaValue cue.Value) error {
 						if lit, ok := syntax.(*ast.BasicLit); ok {
 							typeStr := strings.Trim(lit.Value, "\"")
 							if typeStr != "" && !strings.Contains(typeStr, "_|_") {
-								return fmt.Errorf("def '%s' has invalid type '%s' - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, union, ref",
+								return fmt.Errorf("def '%s' has invalid type '%s' - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, bytevector, bytelist, union, ref",
 									defName, typeStr)
 							}
 						} else if binExpr, ok := syntax.(*ast.BinaryExpr); ok {
@@ -148,7 +164,7 @@ func EnhanceCUEError(err error, schemaValue cue.Value) error {
 										if lit, ok := embedDecl.Expr.(*ast.BasicLit); ok {
 											typeStr := strings.Trim(lit.Value, "\"")
 											if typeStr != "" && !strings.Contains(typeStr, "_|_") {
-												return fmt.Errorf("def '%s' has invalid type '%s' - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, union, ref",
+												return fmt.Errorf("def '%s' has invalid type '%s' - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, bytevector, bytelist, union, ref",
 													defName, typeStr)
 											}
 										}
@@ -157,7 +173,7 @@ func EnhanceCUEError(err error, schemaValue cue.Value) error {
 										if labelLit, ok := field.Label.(*ast.BasicLit); ok {
 											typeStr := strings.Trim(labelLit.Value, "\"")
 											if typeStr != "" && !strings.Contains(typeStr, "_|_") {
-												return fmt.Errorf("def '%s' has invalid type '%s' - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, union, ref",
+												return fmt.Errorf("def '%s' has invalid type '%s' - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, bytevector, bytelist, union, ref",
 													defName, typeStr)
 											}
 										}
@@ -168,7 +184,7 @@ func EnhanceCUEError(err error, schemaValue cue.Value) error {
 					}
 				}
 
-				return fmt.Errorf("def '%s' has invalid type - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, union, ref",
+				return fmt.Errorf("def '%s' has invalid type - must be one of: uint8, uint16, uint32, uint64, uint128, uint256, boolean, container, progressive_container, vector, list, bitvector, bitlist, bytevector, bytelist, union, ref",
 					defName)
 			}
 		}
@@ -264,24 +280,26 @@ func detectCycleInCUE(defName string, defValue, defsValue cue.Value, visited, pa
 
 // refLocation tracks where a reference was found for better error messages
 type refLocation struct {
-	ref  string // The referenced type name
-	path string // Human-readable path where this ref was found
+	ref    string   // The referenced type name
+	path   string   // Human-readable path where this ref was found
+	fields []string // Field names leading to the ref, with "[]" for vector/list elements
 }
 
 // collectRefsFromCUE collects all type references from a CUE def value with location context
 func collectRefsFromCUE(defValue cue.Value) []refLocation {
-	return collectRefsWithPath(defValue, "")
+	return collectRefsWithPath(defValue, "", nil)
 }
 
 // collectRefsWithPath is the internal implementation that tracks the path
-func collectRefsWithPath(defValue cue.Value, pathPrefix string) []refLocation {
+func collectRefsWithPath(defValue cue.Value, pathPrefix string, fields []string) []refLocation {
 	var refs []refLocation
 
 	// Check if this def itself is a ref
+	typeStr := ""
 	typeValue := defValue.LookupPath(cue.ParsePath("type"))
 	if typeValue.Err() == nil {
-		typeStr, err := typeValue.String()
-		if err == nil && typeStr == "ref" {
+		typeStr, _ = typeValue.String()
+		if typeStr == "ref" {
 			// This is a ref type, get the ref field
 			refValue := defValue.LookupPath(cue.ParsePath("ref"))
 			if refValue.Err() == nil {
@@ -290,7 +308,7 @@ func collectRefsWithPath(defValue cue.Value, pathPrefix string) []refLocation {
 					if pathPrefix != "" {
 						location = pathPrefix
 					}
-					refs = append(refs, refLocation{ref: refStr, path: location})
+					refs = append(refs, refLocation{ref: refStr, path: location, fields: fields})
 				}
 			}
 		}
@@ -324,8 +342,15 @@ func collectRefsWithPath(defValue cue.Value, pathPrefix string) []refLocation {
 						childPath = pathPrefix + " -> " + childPath
 					}
 
+					// Vector and list elements are not really fields, so mark them as such
+					childField := childName
+					if typeStr == "vector" || typeStr == "list" {
+						childField = "[]"
+					}
+					childFields := append(slices.Clip(fields), childField)
+
 					// Recursively collect refs from the child def
-					refs = append(refs, collectRefsWithPath(childDef, childPath)...)
+					refs = append(refs, collectRefsWithPath(childDef, childPath, childFields)...)
 				}
 				idx++
 			}
@@ -376,3 +401,24 @@ func checkRefsWithCUE(schemaValue cue.Value) error {
 
 	return nil
 }
+
+// collectRefGraphFromCUE builds the def reference graph: for every def, the refs it
+// contains (directly or through nested fields), in field order
+func collectRefGraphFromCUE(schemaValue cue.Value) (map[string][]refLocation, error) {
+	// Get the defs field
+	defsValue := schemaValue.LookupPath(cue.ParsePath("defs"))
+	if defsValue.Err() != nil {
+		return nil, fmt.Errorf("failed to lookup defs: %w", defsValue.Err())
+	}
+
+	graph := make(map[string][]refLocation)
+	iter, err := defsValue.Fields(cue.Definitions(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate defs: %w", err)
+	}
+	for iter.Next() {
+		graph[iter.Label()] = collectRefsFromCUE(iter.Value())
+	}
+
+	return graph, nil
+}
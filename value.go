@@ -0,0 +1,202 @@
+package cuessz
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Value is the value of a def. Values are plain Go values, so codecs and tools can
+// work on any schema without generated types:
+//
+//	uint8, uint16, uint32, uint64    uint64
+//	uint128, uint256                 *big.Int
+//	boolean                          bool
+//	byte vectors and lists           []byte
+//	bitvector, bitlist               []bool
+//	other vectors and lists          []any
+//	container, progressive_container map[string]any keyed by field name
+//	union                            UnionValue
+//
+// A ref takes the value of the def it references
+// When encoding, any Go integer type is accepted for uints up to uint64 and
+// uint64 is accepted for uint128 and uint256
+type Value = any
+
+// UnionValue is the value of a union def: the index of the selected option and its value
+type UnionValue struct {
+	Selector int
+	Value    any
+}
+
+// resolveRef follows refs until it reaches a def that is not a ref
+func (s *Schema) resolveRef(d *Def) (*Def, error) {
+	for depth := 0; d.Type == TypeRef; depth++ {
+		if depth > maxCycleDepth {
+			return nil, fmt.Errorf("%w: ref chain from '%s' is too deep", ErrRecursiveType, d.Ref)
+		}
+		target, ok := s.Defs[d.Ref]
+		if !ok {
+			return nil, fmt.Errorf("ref type '%s' not found", d.Ref)
+		}
+		d = &target
+	}
+	return d, nil
+}
+
+// lookupDef returns the named def, for the value functions taking a def name
+func (s *Schema) lookupDef(name string) (*Def, error) {
+	def, ok := s.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("def '%s' not found", name)
+	}
+	return &def, nil
+}
+
+// uintBits returns the width of a uint type, or 0 for other types
+func uintBits(t TypeName) int {
+	switch t {
+	case TypeUint8:
+		return 8
+	case TypeUint16:
+		return 16
+	case TypeUint32:
+		return 32
+	case TypeUint64:
+		return 64
+	case TypeUint128:
+		return 128
+	case TypeUint256:
+		return 256
+	default:
+		return 0
+	}
+}
+
+// toUint64 converts any Go integer to uint64, checking it fits in the given width
+func toUint64(v any, width int) (uint64, error) {
+	var n uint64
+	switch x := v.(type) {
+	case uint64:
+		n = x
+	case uint:
+		n = uint64(x)
+	case uint8:
+		n = uint64(x)
+	case uint16:
+		n = uint64(x)
+	case uint32:
+		n = uint64(x)
+	case int, int8, int16, int32, int64:
+		i := toInt64(x)
+		if i < 0 {
+			return 0, fmt.Errorf("negative value %d", i)
+		}
+		n = uint64(i)
+	default:
+		return 0, fmt.Errorf("expected an unsigned integer, got %T", v)
+	}
+	if width < 64 && n>>width != 0 {
+		return 0, fmt.Errorf("value %d overflows uint%d", n, width)
+	}
+	return n, nil
+}
+
+func toInt64(v any) int64 {
+	switch x := v.(type) {
+	case int:
+		return int64(x)
+	case int8:
+		return int64(x)
+	case int16:
+		return int64(x)
+	case int32:
+		return int64(x)
+	default:
+		return x.(int64)
+	}
+}
+
+// toBigUint converts a uint128 or uint256 value to a big.Int, checking its width
+func toBigUint(v any, width int) (*big.Int, error) {
+	var n *big.Int
+	switch x := v.(type) {
+	case *big.Int:
+		if x == nil {
+			return nil, fmt.Errorf("nil *big.Int")
+		}
+		n = x
+	case big.Int:
+		n = &x
+	default:
+		u, err := toUint64(v, 64)
+		if err != nil {
+			return nil, err
+		}
+		n = new(big.Int).SetUint64(u)
+	}
+	if n.Sign() < 0 {
+		return nil, fmt.Errorf("negative value %s", n)
+	}
+	if n.BitLen() > width {
+		return nil, fmt.Errorf("value %s overflows uint%d", n, width)
+	}
+	return n, nil
+}
+
+// packBits packs bits little-endian into bytes, the SSZ layout of a bitvector
+func packBits(bits []bool) []byte {
+	out := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
+
+// packBitlist packs bits followed by the delimiter bit, the SSZ layout of a bitlist
+func packBitlist(bits []bool) []byte {
+	out := make([]byte, len(bits)/8+1)
+	copy(out, packBits(bits))
+	out[len(bits)/8] |= 1 << (len(bits) % 8)
+	return out
+}
+
+// unpackBits unpacks a bitvector of n bits, rejecting set padding bits
+func unpackBits(data []byte, n uint64) ([]bool, error) {
+	if uint64(len(data)) != (n+7)/8 {
+		return nil, fmt.Errorf("bitvector of %d bits needs %d bytes, got %d", n, (n+7)/8, len(data))
+	}
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = data[i/8]&(1<<(i%8)) != 0
+	}
+	if n%8 != 0 && data[len(data)-1]>>(n%8) != 0 {
+		return nil, fmt.Errorf("bitvector of %d bits has padding bits set", n)
+	}
+	return bits, nil
+}
+
+// unpackBitlist unpacks a bitlist, locating its length from the delimiter bit
+func unpackBitlist(data []byte, limit uint64) ([]bool, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("bitlist is empty, missing the delimiter bit")
+	}
+	last := data[len(data)-1]
+	if last == 0 {
+		return nil, fmt.Errorf("bitlist has no delimiter bit in its last byte")
+	}
+	msb := 7
+	for last&(1<<msb) == 0 {
+		msb--
+	}
+	n := uint64(len(data)-1)*8 + uint64(msb)
+	if n > limit {
+		return nil, fmt.Errorf("bitlist has %d bits, limit is %d", n, limit)
+	}
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = data[i/8]&(1<<(i%8)) != 0
+	}
+	return bits, nil
+}
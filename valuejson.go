@@ -0,0 +1,556 @@
+package cuessz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalValueJSON encodes a value of the named def (see Value)
+// as JSON following the beacon-API conventions:
+//   - uints of every width are quoted decimals
+//   - byte vectors and lists are 0x-prefixed hex strings
+//   - bitvectors and bitlists are the hex of their SSZ bytes, so a bitlist
+//     includes its delimiter bit
+//   - unions are {"selector": <n>, "value": <value>}
+//
+// Container fields are written in schema order
+func (s *Schema) MarshalValueJSON(def string, v Value) ([]byte, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.valueTree(d, v, def, false)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeTreeJSON(&buf, tree); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalValueJSON decodes JSON in the MarshalValueJSON form into a value of the named def
+// Uints are also accepted as numbers or strings regardless of width
+func (s *Schema) UnmarshalValueJSON(def string, data []byte) (Value, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("invalid JSON: trailing data after value")
+	}
+	return s.treeValue(d, tree, def)
+}
+
+// MarshalValueYAML encodes a value of the named def as YAML, in the form used by the
+// consensus spec tests: uints are plain integers and bytes and bitfields are quoted hex
+func (s *Schema) MarshalValueYAML(def string, v Value) ([]byte, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.valueTree(d, v, def, true)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(treeYAMLNode(tree))
+}
+
+// UnmarshalValueYAML decodes YAML in the MarshalValueYAML or JSON form into a value
+// of the named def
+func (s *Schema) UnmarshalValueYAML(def string, data []byte) (Value, error) {
+	d, err := s.lookupDef(def)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 {
+		return nil, fmt.Errorf("invalid YAML: expected a single document")
+	}
+	tree, err := yamlNodeTree(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	return s.treeValue(d, tree, def)
+}
+
+// A value tree is the format-neutral form of a value: string, treeNumber, bool,
+// []any and treeObject for encoding, and the equivalent decoded JSON or YAML
+// (json.Number and map[string]any) for decoding
+
+// treeNumber is an unsigned integer written without quotes
+type treeNumber string
+
+// treeObject is an object with its fields in schema order
+type treeObject []treeField
+
+type treeField struct {
+	name  string
+	value any
+}
+
+// valueTree converts a value of d to a value tree; plainUints writes every uint
+// as a number (the YAML form) instead of a quoted decimal
+func (s *Schema) valueTree(d *Def, v any, path string, plainUints bool) (any, error) {
+	d, err := s.resolveRef(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	fail := func(format string, args ...any) (any, error) {
+		return nil, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64:
+		n, err := toUint64(v, uintBits(d.Type))
+		if err != nil {
+			return fail("%v", err)
+		}
+		text := strconv.FormatUint(n, 10)
+		if plainUints {
+			return treeNumber(text), nil
+		}
+		return text, nil
+	case TypeUint128, TypeUint256:
+		n, err := toBigUint(v, uintBits(d.Type))
+		if err != nil {
+			return fail("%v", err)
+		}
+		if plainUints {
+			return treeNumber(n.String()), nil
+		}
+		return n.String(), nil
+	case TypeBoolean:
+		b, ok := v.(bool)
+		if !ok {
+			return fail("expected bool, got %T", v)
+		}
+		return b, nil
+	case TypeBitVector, TypeBitList:
+		bits, ok := v.([]bool)
+		if !ok {
+			return fail("expected []bool, got %T", v)
+		}
+		if d.Type == TypeBitVector {
+			if uint64(len(bits)) != d.Size {
+				return fail("expected %d bits, got %d", d.Size, len(bits))
+			}
+			return "0x" + hex.EncodeToString(packBits(bits)), nil
+		}
+		if uint64(len(bits)) > d.Limit {
+			return fail("%d bits exceed the limit of %d", len(bits), d.Limit)
+		}
+		return "0x" + hex.EncodeToString(packBitlist(bits)), nil
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		if d.IsBytes() {
+			b, ok := v.([]byte)
+			if !ok {
+				return fail("expected []byte, got %T", v)
+			}
+			if err := checkLength(d, uint64(len(b)), "bytes"); err != nil {
+				return fail("%v", err)
+			}
+			return "0x" + hex.EncodeToString(b), nil
+		}
+		items, ok := v.([]any)
+		if !ok {
+			return fail("expected []any, got %T", v)
+		}
+		if err := checkLength(d, uint64(len(items)), "elements"); err != nil {
+			return fail("%v", err)
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			if out[i], err = s.valueTree(&d.Children[0].Def, item, fmt.Sprintf("%s[%d]", path, i), plainUints); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case TypeContainer, TypeProgressiveContainer:
+		fields, ok := v.(map[string]any)
+		if !ok {
+			return fail("expected map[string]any, got %T", v)
+		}
+		if err := checkFieldNames(d, fields); err != nil {
+			return fail("%v", err)
+		}
+		out := make(treeObject, len(d.Children))
+		for i := range d.Children {
+			child := &d.Children[i]
+			value, err := s.valueTree(&child.Def, fields[child.Name], path+"."+child.Name, plainUints)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = treeField{name: child.Name, value: value}
+		}
+		return out, nil
+	case TypeUnion:
+		u, ok := v.(UnionValue)
+		if !ok {
+			return fail("expected UnionValue, got %T", v)
+		}
+		if u.Selector < 0 || u.Selector >= len(d.Children) {
+			return fail("union selector %d out of range (%d options)", u.Selector, len(d.Children))
+		}
+		value, err := s.valueTree(&d.Children[u.Selector].Def, u.Value, path+"."+d.Children[u.Selector].Name, plainUints)
+		if err != nil {
+			return nil, err
+		}
+		return treeObject{
+			{name: "selector", value: treeNumber(strconv.Itoa(u.Selector))},
+			{name: "value", value: value},
+		}, nil
+	default:
+		return fail("invalid type '%s'", d.Type)
+	}
+}
+
+// checkLength checks the length of a vector (exact) or list (at most the limit)
+func checkLength(d *Def, n uint64, unit string) error {
+	switch d.Type {
+	case TypeVector, TypeByteVector:
+		if n != d.Size {
+			return fmt.Errorf("expected %d %s, got %d", d.Size, unit, n)
+		}
+	default:
+		if n > d.Limit {
+			return fmt.Errorf("%d %s exceed the limit of %d", n, unit, d.Limit)
+		}
+	}
+	return nil
+}
+
+// checkFieldNames checks a container value has exactly the fields of d
+func checkFieldNames(d *Def, fields map[string]any) error {
+	for i := range d.Children {
+		if _, ok := fields[d.Children[i].Name]; !ok {
+			return fmt.Errorf("missing field '%s'", d.Children[i].Name)
+		}
+	}
+	if len(fields) != len(d.Children) {
+		for name := range fields {
+			if !hasChild(d, name) {
+				return fmt.Errorf("unknown field '%s'", name)
+			}
+		}
+	}
+	return nil
+}
+
+func hasChild(d *Def, name string) bool {
+	for i := range d.Children {
+		if d.Children[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// treeValue converts a decoded JSON or YAML tree to a value of d
+func (s *Schema) treeValue(d *Def, tree any, path string) (any, error) {
+	d, err := s.resolveRef(d)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	fail := func(format string, args ...any) (any, error) {
+		return nil, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64, TypeUint128, TypeUint256:
+		text, ok := treeDecimal(tree)
+		if !ok {
+			return fail("expected %s as a number or decimal string, got %s", d.Type, treeKind(tree))
+		}
+		n, ok := new(big.Int).SetString(text, 10)
+		if !ok || n.Sign() < 0 {
+			return fail("invalid %s '%s'", d.Type, text)
+		}
+		width := uintBits(d.Type)
+		if n.BitLen() > width {
+			return fail("value %s overflows %s", text, d.Type)
+		}
+		if width <= 64 {
+			return n.Uint64(), nil
+		}
+		return n, nil
+	case TypeBoolean:
+		b, ok := tree.(bool)
+		if !ok {
+			return fail("expected bool, got %s", treeKind(tree))
+		}
+		return b, nil
+	case TypeBitVector, TypeBitList:
+		data, err := treeHex(tree)
+		if err != nil {
+			return fail("%v", err)
+		}
+		var bits []bool
+		if d.Type == TypeBitVector {
+			bits, err = unpackBits(data, d.Size)
+		} else {
+			bits, err = unpackBitlist(data, d.Limit)
+		}
+		if err != nil {
+			return fail("%v", err)
+		}
+		return bits, nil
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		if d.IsBytes() {
+			data, err := treeHex(tree)
+			if err != nil {
+				return fail("%v", err)
+			}
+			if err := checkLength(d, uint64(len(data)), "bytes"); err != nil {
+				return fail("%v", err)
+			}
+			return data, nil
+		}
+		items, ok := tree.([]any)
+		if !ok {
+			return fail("expected array, got %s", treeKind(tree))
+		}
+		if err := checkLength(d, uint64(len(items)), "elements"); err != nil {
+			return fail("%v", err)
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			if out[i], err = s.treeValue(&d.Children[0].Def, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case TypeContainer, TypeProgressiveContainer:
+		fields, ok := tree.(map[string]any)
+		if !ok {
+			return fail("expected object, got %s", treeKind(tree))
+		}
+		if err := checkFieldNames(d, fields); err != nil {
+			return fail("%v", err)
+		}
+		out := make(map[string]any, len(d.Children))
+		for i := range d.Children {
+			child := &d.Children[i]
+			if out[child.Name], err = s.treeValue(&child.Def, fields[child.Name], path+"."+child.Name); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case TypeUnion:
+		fields, ok := tree.(map[string]any)
+		if !ok {
+			return fail("expected {selector, value} object, got %s", treeKind(tree))
+		}
+		text, ok := treeDecimal(fields["selector"])
+		if !ok {
+			return fail("union selector must be a number")
+		}
+		selector, err := strconv.Atoi(text)
+		if err != nil || selector < 0 || selector >= len(d.Children) {
+			return fail("union selector %s out of range (%d options)", text, len(d.Children))
+		}
+		if len(fields) != 2 {
+			return fail("union must have exactly the fields 'selector' and 'value'")
+		}
+		value, ok := fields["value"]
+		if !ok {
+			return fail("missing field 'value'")
+		}
+		option := &d.Children[selector]
+		v, err := s.treeValue(&option.Def, value, path+"."+option.Name)
+		if err != nil {
+			return nil, err
+		}
+		return UnionValue{Selector: selector, Value: v}, nil
+	default:
+		return fail("invalid type '%s'", d.Type)
+	}
+}
+
+// treeDecimal returns the text of a number or decimal string
+func treeDecimal(tree any) (string, bool) {
+	switch x := tree.(type) {
+	case json.Number:
+		return x.String(), true
+	case string:
+		return x, x != ""
+	default:
+		return "", false
+	}
+}
+
+// treeHex decodes a 0x-prefixed hex string
+func treeHex(tree any) ([]byte, error) {
+	text, ok := tree.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected 0x-prefixed hex string, got %s", treeKind(tree))
+	}
+	digits, ok := strings.CutPrefix(text, "0x")
+	if !ok {
+		return nil, fmt.Errorf("hex string '%s' must start with 0x", text)
+	}
+	data, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string: %w", err)
+	}
+	return data, nil
+}
+
+// treeKind names the JSON kind of a decoded tree for error messages
+func treeKind(tree any) string {
+	switch tree.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", tree)
+	}
+}
+
+// writeTreeJSON writes a value tree as compact JSON
+func writeTreeJSON(buf *bytes.Buffer, tree any) error {
+	switch x := tree.(type) {
+	case treeNumber:
+		buf.WriteString(string(x))
+	case string:
+		data, err := json.Marshal(x)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	case bool:
+		buf.WriteString(strconv.FormatBool(x))
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range x {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeTreeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case treeObject:
+		buf.WriteByte('{')
+		for i, field := range x {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name, err := json.Marshal(field.name)
+			if err != nil {
+				return err
+			}
+			buf.Write(name)
+			buf.WriteByte(':')
+			if err := writeTreeJSON(buf, field.value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected %T in value tree", tree)
+	}
+	return nil
+}
+
+// treeYAMLNode converts a value tree to a YAML node, quoting hex strings
+func treeYAMLNode(tree any) *yaml.Node {
+	switch x := tree.(type) {
+	case treeNumber:
+		// No tag: yaml.v3 would write !!int on uint256 values too big for its own int parsing
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: string(x)}
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: x, Style: yaml.SingleQuotedStyle}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(x)}
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range x {
+			node.Content = append(node.Content, treeYAMLNode(item))
+		}
+		return node
+	case treeObject:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, field := range x {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.name},
+				treeYAMLNode(field.value))
+		}
+		return node
+	}
+	panic(fmt.Sprintf("unexpected %T in value tree", tree))
+}
+
+// yamlNodeTree converts a YAML node to the tree UnmarshalValueJSON decodes,
+// keeping integers as text so uint256 values do not lose precision
+func yamlNodeTree(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return yamlNodeTree(node.Alias)
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!int", "!!float":
+			// Integers beyond 64 bits resolve as !!float; keep the text for treeValue
+			return json.Number(node.Value), nil
+		case "!!bool":
+			var b bool
+			if err := node.Decode(&b); err != nil {
+				return nil, err
+			}
+			return b, nil
+		case "!!null":
+			return nil, nil
+		default:
+			return node.Value, nil
+		}
+	case yaml.SequenceNode:
+		out := make([]any, len(node.Content))
+		for i, item := range node.Content {
+			v, err := yamlNodeTree(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case yaml.MappingNode:
+		out := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v, err := yamlNodeTree(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			out[node.Content[i].Value] = v
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+	}
+}
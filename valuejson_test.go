@@ -0,0 +1,182 @@
+package cuessz
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func valueSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema := NewSchema().
+		Def("Root", ByteVector(4)).
+		Add(
+			Container("Checkpoint").
+				Field("epoch", Uint64()).
+				Field("root", Ref("Root")),
+			Container("Mixed").
+				Field("flags", Uint8()).
+				Field("bits", BitList(16)).
+				Field("justification", BitVector(4)).
+				Field("extra_data", ByteList(8)).
+				Field("balance", Uint256()).
+				Field("checkpoints", List(Ref("Checkpoint"), 4)).
+				Field("ok", Boolean()),
+		).
+		MustBuild()
+	schema.Defs["Choice"] = Def{Type: TypeUnion, Children: []Field{
+		{Name: "count", Def: Uint16()},
+		{Name: "checkpoint", Def: Ref("Checkpoint")},
+	}}
+	return schema
+}
+
+func mixedValue() map[string]any {
+	balance, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	return map[string]any{
+		"flags":         uint64(7),
+		"bits":          []bool{true, false, true},
+		"justification": []bool{false, true, true, false},
+		"extra_data":    []byte{0xca, 0xfe},
+		"balance":       balance,
+		"checkpoints": []any{
+			map[string]any{"epoch": uint64(18446744073709551615), "root": []byte{1, 2, 3, 4}},
+		},
+		"ok": true,
+	}
+}
+
+func TestValueJSON_RoundTrip(t *testing.T) {
+	schema := valueSchema(t)
+
+	data, err := schema.MarshalValueJSON("Mixed", mixedValue())
+	if err != nil {
+		t.Fatalf("MarshalValueJSON failed: %v", err)
+	}
+	want := `{"flags":"7","bits":"0x0d","justification":"0x06","extra_data":"0xcafe",` +
+		`"balance":"115792089237316195423570985008687907853269984665640564039457584007913129639935",` +
+		`"checkpoints":[{"epoch":"18446744073709551615","root":"0x01020304"}],"ok":true}`
+	if string(data) != want {
+		t.Errorf("unexpected JSON:\n got  %s\n want %s", data, want)
+	}
+
+	value, err := schema.UnmarshalValueJSON("Mixed", data)
+	if err != nil {
+		t.Fatalf("UnmarshalValueJSON failed: %v", err)
+	}
+	if !reflect.DeepEqual(value, mixedValue()) {
+		t.Errorf("round trip mismatch:\n got  %#v\n want %#v", value, mixedValue())
+	}
+}
+
+func TestValueYAML_RoundTrip(t *testing.T) {
+	schema := valueSchema(t)
+
+	data, err := schema.MarshalValueYAML("Mixed", mixedValue())
+	if err != nil {
+		t.Fatalf("MarshalValueYAML failed: %v", err)
+	}
+	for _, want := range []string{"flags: 7\n", "bits: '0x0d'\n", "epoch: 18446744073709551615\n", "balance: 1157920892"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected YAML to contain %q:\n%s", want, data)
+		}
+	}
+
+	value, err := schema.UnmarshalValueYAML("Mixed", data)
+	if err != nil {
+		t.Fatalf("UnmarshalValueYAML failed: %v", err)
+	}
+	if !reflect.DeepEqual(value, mixedValue()) {
+		t.Errorf("round trip mismatch:\n got  %#v\n want %#v", value, mixedValue())
+	}
+}
+
+func TestValueJSON_Union(t *testing.T) {
+	schema := valueSchema(t)
+
+	v := UnionValue{Selector: 1, Value: map[string]any{"epoch": uint64(3), "root": []byte{0, 0, 0, 1}}}
+	data, err := schema.MarshalValueJSON("Choice", v)
+	if err != nil {
+		t.Fatalf("MarshalValueJSON failed: %v", err)
+	}
+	if want := `{"selector":1,"value":{"epoch":"3","root":"0x00000001"}}`; string(data) != want {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+
+	got, err := schema.UnmarshalValueJSON("Choice", data)
+	if err != nil {
+		t.Fatalf("UnmarshalValueJSON failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("round trip mismatch: %#v", got)
+	}
+}
+
+func TestValueJSON_Errors(t *testing.T) {
+	schema := valueSchema(t)
+
+	marshal := []struct {
+		def   string
+		value any
+		want  string
+	}{
+		{"Root", []byte{1, 2, 3}, "Root: expected 4 bytes, got 3"},
+		{"Checkpoint", map[string]any{"epoch": uint64(1)}, "missing field 'root'"},
+		{"Checkpoint", map[string]any{"epoch": uint64(1), "root": []byte{1, 2, 3, 4}, "extra": 1}, "unknown field 'extra'"},
+		{"Checkpoint", map[string]any{"epoch": -1, "root": []byte{1, 2, 3, 4}}, "Checkpoint.epoch: negative value"},
+		{"Choice", UnionValue{Selector: 2}, "selector 2 out of range"},
+		{"Missing", nil, "def 'Missing' not found"},
+	}
+	for _, tt := range marshal {
+		_, err := schema.MarshalValueJSON(tt.def, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("MarshalValueJSON(%s, %v): expected error containing %q, got: %v", tt.def, tt.value, tt.want, err)
+		}
+	}
+
+	unmarshal := []struct {
+		def  string
+		json string
+		want string
+	}{
+		{"Root", `"01020304"`, "must start with 0x"},
+		{"Root", `"0x010203zz"`, "invalid hex"},
+		{"Checkpoint", `{"epoch":"1.5","root":"0x01020304"}`, "Checkpoint.epoch: invalid uint64"},
+		{"Checkpoint", `{"epoch":"18446744073709551616","root":"0x01020304"}`, "overflows uint64"},
+		{"Mixed", `{"flags":256}`, "missing field"},
+		{"Checkpoint", `[]`, "expected object, got array"},
+		{"Checkpoint", `{"epoch":"1","root":"0x01020304"} {}`, "trailing data"},
+		{"Choice", `{"selector":0}`, "exactly the fields"},
+	}
+	for _, tt := range unmarshal {
+		_, err := schema.UnmarshalValueJSON(tt.def, []byte(tt.json))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("UnmarshalValueJSON(%s, %s): expected error containing %q, got: %v", tt.def, tt.json, tt.want, err)
+		}
+	}
+}
+
+func TestBitfieldPacking(t *testing.T) {
+	bits := []bool{true, false, false, false, false, false, false, false, true}
+	if got := packBitlist(bits); !reflect.DeepEqual(got, []byte{0x01, 0x03}) {
+		t.Errorf("packBitlist = %x, want 0103", got)
+	}
+	if got := packBitlist(nil); !reflect.DeepEqual(got, []byte{0x01}) {
+		t.Errorf("packBitlist(nil) = %x, want 01", got)
+	}
+
+	got, err := unpackBitlist([]byte{0x01, 0x03}, 16)
+	if err != nil || !reflect.DeepEqual(got, bits) {
+		t.Errorf("unpackBitlist = %v, %v", got, err)
+	}
+	if _, err := unpackBitlist([]byte{0x01, 0x00}, 16); err == nil {
+		t.Error("expected error for missing delimiter bit")
+	}
+	if _, err := unpackBitlist([]byte{0x01, 0x03}, 8); err == nil {
+		t.Error("expected error for bitlist over its limit")
+	}
+	if _, err := unpackBits([]byte{0x10}, 4); err == nil {
+		t.Error("expected error for bitvector padding bits")
+	}
+}
@@ -0,0 +1,131 @@
+package cuessz
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Warning is a non-fatal finding about an otherwise valid schema
+type Warning struct {
+	Def     string // The def the warning is about, if any
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Def == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("def '%s': %s", w.Def, w.Message)
+}
+
+// VetOptions configures the checks VetJSON runs on top of ParseJSON
+type VetOptions struct {
+	// Roots are entry-point defs in addition to the schema's own roots
+	// Unused defs are only reported when at least one root is declared
+	Roots []string
+}
+
+// VetJSON parses and validates a JSON schema like ParseJSON and additionally reports
+// defs that are unreachable from the declared roots and defs that are structurally
+// identical to one another
+func VetJSON(data []byte, opts VetOptions) (*Schema, []Warning, error) {
+	schema, unified, err := parseJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graph, err := collectRefGraphFromCUE(unified)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roots := append(slices.Clone(schema.Roots), opts.Roots...)
+	warnings := findUnusedDefs(graph, roots)
+
+	duplicates, err := findDuplicateDefs(schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings = append(warnings, duplicates...)
+
+	return schema, warnings, nil
+}
+
+// findUnusedDefs walks the ref graph from the roots and reports every def it never reaches
+func findUnusedDefs(graph map[string][]refLocation, roots []string) []Warning {
+	if len(roots) == 0 {
+		return nil
+	}
+
+	var warnings []Warning
+	reached := make(map[string]bool)
+	queue := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if _, ok := graph[root]; !ok {
+			warnings = append(warnings, Warning{Message: fmt.Sprintf("root '%s' is not defined in schema defs", root)})
+			continue
+		}
+		if !reached[root] {
+			reached[root] = true
+			queue = append(queue, root)
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, refLoc := range graph[name] {
+			if !reached[refLoc.ref] {
+				reached[refLoc.ref] = true
+				queue = append(queue, refLoc.ref)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(graph)) {
+		if !reached[name] {
+			warnings = append(warnings, Warning{Def: name, Message: "not referenced from any root"})
+		}
+	}
+	return warnings
+}
+
+// findDuplicateDefs reports groups of defs with the same shape and field names,
+// comparing them by fingerprint with refs expanded
+func findDuplicateDefs(schema *Schema) ([]Warning, error) {
+	fp := newFingerprinter(schema.Defs)
+	fp.fieldNames = true
+
+	groups := make(map[Fingerprint][]string)
+	for _, name := range slices.Sorted(maps.Keys(schema.Defs)) {
+		f, err := fp.ref(name)
+		if err != nil {
+			return nil, fmt.Errorf("def '%s': %w", name, err)
+		}
+		groups[f] = append(groups[f], name)
+	}
+
+	var warnings []Warning
+	for _, names := range groups {
+		if len(names) < 2 {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Def:     names[0],
+			Message: fmt.Sprintf("structurally identical to %s", strings.Join(quoteAll(names[1:]), ", ")),
+		})
+	}
+	slices.SortFunc(warnings, func(a, b Warning) int { return strings.Compare(a.Def, b.Def) })
+	return warnings, nil
+}
+
+// quoteAll wraps each name in single quotes to match the error message style
+func quoteAll(names []string) []string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = "'" + name + "'"
+	}
+	return out
+}
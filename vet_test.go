@@ -0,0 +1,133 @@
+package cuessz
+
+import (
+	"strings"
+	"testing"
+)
+
+var vetSchema = []byte(`{
+	"version": "1.0.0",
+	"roots": ["SignedBlock"],
+	"defs": {
+		"Root": {"type": "bytevector", "size": 32},
+		"SigningRoot": {"type": "bytevector", "size": 32},
+		"Block": {
+			"type": "container",
+			"children": [
+				{"name": "slot", "def": {"type": "uint64"}},
+				{"name": "parent_root", "def": {"type": "ref", "ref": "Root"}}
+			]
+		},
+		"SignedBlock": {
+			"type": "container",
+			"children": [
+				{"name": "message", "def": {"type": "ref", "ref": "Block"}},
+				{"name": "signature", "def": {"type": "bytevector", "size": 96}}
+			]
+		},
+		"Transfer": {
+			"type": "container",
+			"children": [
+				{"name": "amount", "def": {"type": "uint64"}}
+			]
+		},
+		"Checkpoint": {
+			"type": "container",
+			"children": [
+				{"name": "epoch", "def": {"type": "uint64"}},
+				{"name": "root", "def": {"type": "ref", "ref": "Root"}}
+			]
+		}
+	}
+}`)
+
+func warningsFor(warnings []Warning, def string) []string {
+	var out []string
+	for _, w := range warnings {
+		if w.Def == def {
+			out = append(out, w.Message)
+		}
+	}
+	return out
+}
+
+func TestVetJSON_Unused(t *testing.T) {
+	_, warnings, err := VetJSON(vetSchema, VetOptions{})
+	if err != nil {
+		t.Fatalf("VetJSON failed: %v", err)
+	}
+
+	for _, name := range []string{"Transfer", "Checkpoint", "SigningRoot"} {
+		found := false
+		for _, msg := range warningsFor(warnings, name) {
+			if strings.Contains(msg, "not referenced") {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %s to be reported as unused, got: %v", name, warnings)
+		}
+	}
+	for _, name := range []string{"SignedBlock", "Block", "Root"} {
+		for _, msg := range warningsFor(warnings, name) {
+			if strings.Contains(msg, "not referenced") {
+				t.Errorf("expected %s to be reachable, got: %s", name, msg)
+			}
+		}
+	}
+
+	// Extra roots from the options are honored too
+	_, warnings, err = VetJSON(vetSchema, VetOptions{Roots: []string{"Checkpoint", "Missing"}})
+	if err != nil {
+		t.Fatalf("VetJSON failed: %v", err)
+	}
+	if msgs := warningsFor(warnings, "Checkpoint"); len(msgs) != 0 {
+		t.Errorf("expected Checkpoint to be reachable from extra root, got: %v", msgs)
+	}
+	found := false
+	for _, w := range warnings {
+		if strings.Contains(w.Message, "root 'Missing' is not defined") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected warning for undefined root, got: %v", warnings)
+	}
+}
+
+func TestVetJSON_NoRoots(t *testing.T) {
+	schema := []byte(`{"defs": {"A": {"type": "uint64"}, "B": {"type": "uint32"}}}`)
+	_, warnings, err := VetJSON(schema, VetOptions{})
+	if err != nil {
+		t.Fatalf("VetJSON failed: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings without roots, got: %v", warnings)
+	}
+}
+
+func TestVetJSON_Duplicates(t *testing.T) {
+	_, warnings, err := VetJSON(vetSchema, VetOptions{})
+	if err != nil {
+		t.Fatalf("VetJSON failed: %v", err)
+	}
+
+	msgs := warningsFor(warnings, "Root")
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "structurally identical to 'SigningRoot'") {
+		t.Errorf("expected Root and SigningRoot to be reported as duplicates, got: %v", warnings)
+	}
+
+	// Same shape but different field names is not a duplicate
+	for _, w := range warnings {
+		if strings.Contains(w.Message, "identical") && (w.Def == "Block" || w.Def == "Checkpoint") {
+			t.Errorf("expected Block and Checkpoint not to be duplicates, got: %s", w)
+		}
+	}
+}
+
+func TestVetJSON_InvalidSchema(t *testing.T) {
+	_, _, err := VetJSON([]byte(`{"defs": {"A": {"type": "ref", "ref": "B"}}}`), VetOptions{})
+	if err == nil {
+		t.Error("expected error for invalid schema, got nil")
+	}
+}
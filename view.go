@@ -0,0 +1,418 @@
+package cuessz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// View is a lazy, zero-copy window onto the SSZ bytes of a value. Accessors
+// compute offsets on demand from the def tree, so walking into a large state
+// decodes nothing but the offsets on the way and does not allocate:
+//
+//	balance, err := schema.View("BeaconState", buf).
+//		Field("validators").Index(5).Field("effective_balance").Uint64()
+//
+// Navigation errors are carried along the chain and returned by the final
+// accessor. Views check the bounds of what they read but are not a full
+// validation of the data; use UnmarshalSSZ for that
+type View struct {
+	schema *Schema
+	def    Def
+	data   []byte
+	offset uint64
+	err    error
+}
+
+// View returns a view of buf as a value of the named def
+func (s *Schema) View(def string, buf []byte) View {
+	d, ok := s.Defs[def]
+	if !ok {
+		return View{err: fmt.Errorf("def '%s' not found", def)}
+	}
+	return s.newView(d, buf, 0)
+}
+
+// newView resolves refs by value, which unlike resolveRef does not allocate
+func (s *Schema) newView(d Def, buf []byte, offset uint64) View {
+	for depth := 0; d.Type == TypeRef; depth++ {
+		target, ok := s.Defs[d.Ref]
+		if !ok || depth > maxCycleDepth {
+			return View{err: fmt.Errorf("ref type '%s' not found", d.Ref)}
+		}
+		d = target
+	}
+	return View{schema: s, def: d, data: buf, offset: offset}
+}
+
+// Err returns the first error met while navigating to the view
+func (v View) Err() error {
+	return v.err
+}
+
+// Def returns the def of the view with refs resolved
+func (v View) Def() Def {
+	return v.def
+}
+
+// Offset returns the position of the view in the buffer it was created from
+func (v View) Offset() uint64 {
+	return v.offset
+}
+
+// SSZ returns the bytes of the view, aliasing the underlying buffer
+func (v View) SSZ() ([]byte, error) {
+	return v.data, v.err
+}
+
+// Field returns the view of a container field
+func (v View) Field(name string) View {
+	if v.err != nil {
+		return v
+	}
+	if v.def.Type != TypeContainer && v.def.Type != TypeProgressiveContainer {
+		return v.fail("field '%s': %s has no fields", name, v.def.Type)
+	}
+
+	var pos uint64
+	target := -1
+	var start, startAt uint64
+	for i := range v.def.Children {
+		child := &v.def.Children[i]
+		size, variable := v.schema.viewFixedSize(child.Def, 0)
+		if target < 0 && child.Name != name {
+			pos += size
+			if variable {
+				pos += bytesPerOffset
+			}
+			continue
+		}
+
+		if target < 0 {
+			target = i
+			if !variable {
+				if pos+size > uint64(len(v.data)) {
+					return v.failAt(uint64(len(v.data)), ErrTruncated, "field '%s': bytes %d to %d are past the end at %d", name, pos, pos+size, len(v.data))
+				}
+				return v.schema.newView(child.Def, v.data[pos:pos+size], v.offset+pos)
+			}
+			offset, ok := v.readOffset(pos)
+			if !ok {
+				return v.failAt(uint64(len(v.data)), ErrTruncated, "field '%s': offset at byte %d is past the end at %d", name, pos, len(v.data))
+			}
+			start, startAt = offset, pos
+			pos += bytesPerOffset
+			continue
+		}
+
+		// The field ends where the next variable-size field starts
+		if variable {
+			end, ok := v.readOffset(pos)
+			if !ok {
+				return v.failAt(uint64(len(v.data)), ErrTruncated, "field '%s': offset at byte %d is past the end at %d", name, pos, len(v.data))
+			}
+			if view, ok := v.slice(v.def.Children[target].Def, start, end); ok {
+				return view
+			}
+			return v.failAt(startAt, ErrOffset, "field '%s': offsets %d to %d are out of order or out of bounds (size %d)", name, start, end, len(v.data))
+		}
+		pos += size
+	}
+	if target < 0 {
+		return v.fail("field '%s': no such field", name)
+	}
+	if view, ok := v.slice(v.def.Children[target].Def, start, uint64(len(v.data))); ok {
+		return view
+	}
+	return v.failAt(startAt, ErrOffset, "field '%s': offset %d is out of bounds (size %d)", name, start, len(v.data))
+}
+
+// Index returns the view of an element of a vector or list
+func (v View) Index(i int) View {
+	if v.err != nil {
+		return v
+	}
+	if !v.isSequence() {
+		return v.fail("index %d: %s has no elements", i, v.def.Type)
+	}
+	n, err := v.Len()
+	if err != nil {
+		return View{err: err}
+	}
+	if i < 0 || i >= n {
+		return v.fail("index %d out of range (%d elements)", i, n)
+	}
+
+	elem := v.elem()
+	size, variable := v.schema.viewFixedSize(elem, 0)
+	if !variable {
+		at := uint64(i) * size
+		return v.schema.newView(elem, v.data[at:at+size], v.offset+at)
+	}
+	// The offsets all lie before the first one, which Len checked is in bounds
+	start, _ := v.readOffset(uint64(i) * bytesPerOffset)
+	end := uint64(len(v.data))
+	if i+1 < n {
+		end, _ = v.readOffset(uint64(i+1) * bytesPerOffset)
+	}
+	if view, ok := v.slice(elem, start, end); ok {
+		return view
+	}
+	return v.failAt(uint64(i)*bytesPerOffset, ErrOffset, "index %d: offsets %d to %d are out of order or out of bounds (size %d)", i, start, end, len(v.data))
+}
+
+// Len returns the number of elements of a vector or list, or bits of a bitfield
+func (v View) Len() (int, error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	switch v.def.Type {
+	case TypeBitVector:
+		if err := checkFixedSize((v.def.Size+7)/8, false, v.data, v.offset, string(v.def.Type)); err != nil {
+			return 0, err
+		}
+		if v.def.Size%8 != 0 && v.data[len(v.data)-1]>>(v.def.Size%8) != 0 {
+			return 0, v.malformed(uint64(len(v.data)-1), ErrInvalidBitfield, "bitvector of %d bits has padding bits set", v.def.Size)
+		}
+		return int(v.def.Size), nil
+	case TypeBitList:
+		if len(v.data) == 0 || v.data[len(v.data)-1] == 0 {
+			return 0, v.malformed(uint64(max(len(v.data), 1)-1), ErrInvalidBitfield, "bitlist has no delimiter bit in its last byte")
+		}
+		last := v.data[len(v.data)-1]
+		msb := 7
+		for last&(1<<msb) == 0 {
+			msb--
+		}
+		return (len(v.data)-1)*8 + msb, nil
+	}
+	if !v.isSequence() {
+		return 0, fmt.Errorf("%s has no length", v.def.Type)
+	}
+
+	size, variable := v.schema.viewFixedSize(v.elem(), 0)
+	if !variable {
+		if size == 0 || uint64(len(v.data))%size != 0 {
+			return 0, v.malformed(0, ErrLength, "%d bytes is not a multiple of the element size %d", len(v.data), size)
+		}
+		return int(uint64(len(v.data)) / size), nil
+	}
+	if len(v.data) == 0 {
+		return 0, nil
+	}
+	first, ok := v.readOffset(0)
+	if !ok {
+		return 0, v.malformed(0, ErrTruncated, "%d bytes is too short for the first offset", len(v.data))
+	}
+	if first == 0 || first%bytesPerOffset != 0 || first > uint64(len(v.data)) {
+		return 0, v.malformed(0, ErrOffset, "first offset %d is not a positive multiple of %d", first, bytesPerOffset)
+	}
+	return int(first / bytesPerOffset), nil
+}
+
+// Selector returns the selected option of a union
+func (v View) Selector() (int, error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	if v.def.Type != TypeUnion {
+		return 0, fmt.Errorf("%s is not a union", v.def.Type)
+	}
+	if len(v.data) == 0 {
+		return 0, v.malformed(0, ErrTruncated, "union is empty, missing the selector byte")
+	}
+	if int(v.data[0]) >= len(v.def.Children) {
+		return 0, v.malformed(0, ErrInvalidSelector, "union selector %d out of range (%d options)", v.data[0], len(v.def.Children))
+	}
+	return int(v.data[0]), nil
+}
+
+// Option returns the view of the selected value of a union
+func (v View) Option() View {
+	selector, err := v.Selector()
+	if err != nil {
+		return View{err: err}
+	}
+	return v.schema.newView(v.def.Children[selector].Def, v.data[1:], v.offset+1)
+}
+
+// Uint64 returns the value of a uint8, uint16, uint32 or uint64 view
+func (v View) Uint64() (uint64, error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	bits := uintBits(v.def.Type)
+	if bits == 0 || bits > 64 {
+		return 0, fmt.Errorf("%s is not a uint of at most 64 bits", v.def.Type)
+	}
+	if err := checkFixedSize(uint64(bits/8), false, v.data, v.offset, string(v.def.Type)); err != nil {
+		return 0, err
+	}
+	var n uint64
+	for i := len(v.data) - 1; i >= 0; i-- {
+		n = n<<8 | uint64(v.data[i])
+	}
+	return n, nil
+}
+
+// BigInt returns the value of any uint view
+func (v View) BigInt() (*big.Int, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	if uintBits(v.def.Type) == 0 {
+		return nil, fmt.Errorf("%s is not a uint", v.def.Type)
+	}
+	value, err := v.schema.decodeSSZ(&v.def, v.data, v.offset, string(v.def.Type))
+	if err != nil {
+		return nil, err
+	}
+	if n, ok := value.(uint64); ok {
+		return new(big.Int).SetUint64(n), nil
+	}
+	return value.(*big.Int), nil
+}
+
+// Bool returns the value of a boolean view
+func (v View) Bool() (bool, error) {
+	if v.err != nil {
+		return false, v.err
+	}
+	if v.def.Type != TypeBoolean {
+		return false, fmt.Errorf("%s is not a boolean", v.def.Type)
+	}
+	if err := checkFixedSize(1, false, v.data, v.offset, string(v.def.Type)); err != nil {
+		return false, err
+	}
+	if v.data[0] > 1 {
+		return false, v.malformed(0, ErrInvalidBoolean, "invalid boolean byte 0x%02x", v.data[0])
+	}
+	return v.data[0] == 1, nil
+}
+
+// Bytes returns the contents of a byte vector or list, aliasing the underlying buffer
+func (v View) Bytes() ([]byte, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	if !v.def.IsBytes() {
+		return nil, fmt.Errorf("%s is not a byte array", v.def.Type)
+	}
+	return v.data, nil
+}
+
+// Bit returns bit i of a bitvector or bitlist
+func (v View) Bit(i int) (bool, error) {
+	if v.def.Type != TypeBitVector && v.def.Type != TypeBitList && v.err == nil {
+		return false, fmt.Errorf("%s is not a bitfield", v.def.Type)
+	}
+	n, err := v.Len()
+	if err != nil {
+		return false, err
+	}
+	if i < 0 || i >= n {
+		return false, fmt.Errorf("bit %d out of range (%d bits)", i, n)
+	}
+	return v.data[i/8]&(1<<(i%8)) != 0, nil
+}
+
+// Decode decodes the view into a Value (see UnmarshalSSZ)
+func (v View) Decode() (Value, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	return v.schema.decodeSSZ(&v.def, v.data, v.offset, string(v.def.Type))
+}
+
+// elem returns the element def of a sequence view: uint8 for byte arrays,
+// which have no element field in shorthand
+func (v View) elem() Def {
+	if v.def.IsBytes() {
+		return Def{Type: TypeUint8}
+	}
+	return v.def.Children[0].Def
+}
+
+func (v View) isSequence() bool {
+	switch v.def.Type {
+	case TypeVector, TypeList, TypeByteVector, TypeByteList:
+		return true
+	default:
+		return false
+	}
+}
+
+// readOffset reads the 4-byte offset at pos, or reports false past the end
+func (v View) readOffset(pos uint64) (uint64, bool) {
+	if pos+bytesPerOffset > uint64(len(v.data)) {
+		return 0, false
+	}
+	return uint64(binary.LittleEndian.Uint32(v.data[pos:])), true
+}
+
+// slice returns the view of d over data[start:end], or false when those
+// offsets are out of order or out of bounds
+func (v View) slice(d Def, start, end uint64) (View, bool) {
+	if start > end || end > uint64(len(v.data)) {
+		return View{}, false
+	}
+	return v.schema.newView(d, v.data[start:end], v.offset+start), true
+}
+
+func (v View) fail(format string, args ...any) View {
+	return View{err: fmt.Errorf(format, args...)}
+}
+
+// failAt is fail for malformed bytes at pos of the view
+func (v View) failAt(pos uint64, reason error, format string, args ...any) View {
+	return View{err: v.malformed(pos, reason, format, args...)}
+}
+
+// malformed returns a DecodeError for the bytes at pos of the view, with the
+// def type as its path like Decode
+func (v View) malformed(pos uint64, reason error, format string, args ...any) error {
+	return decodeError(string(v.def.Type), v.offset+pos, reason, format, args...)
+}
+
+// viewFixedSize is sszFixedSize over defs passed by value, so views do not
+// allocate; it reports variable for defs it cannot size, including sizes that
+// overflow uint64
+func (s *Schema) viewFixedSize(d Def, depth int) (uint64, bool) {
+	for d.Type == TypeRef && depth <= maxCycleDepth {
+		d = s.Defs[d.Ref]
+		depth++
+	}
+	if depth > maxCycleDepth {
+		return 0, true
+	}
+
+	switch d.Type {
+	case TypeUint8, TypeUint16, TypeUint32, TypeUint64, TypeUint128, TypeUint256:
+		return uint64(uintBits(d.Type) / 8), false
+	case TypeBoolean:
+		return 1, false
+	case TypeBitVector:
+		return (d.Size + 7) / 8, false
+	case TypeByteVector:
+		return d.Size, false
+	case TypeVector:
+		elem, variable := s.viewFixedSize(d.Children[0].Def, depth+1)
+		hi, size := bits.Mul64(elem, d.Size)
+		if variable || hi != 0 {
+			return 0, true
+		}
+		return size, false
+	case TypeContainer, TypeProgressiveContainer:
+		var total, carry uint64
+		for i := range d.Children {
+			size, variable := s.viewFixedSize(d.Children[i].Def, depth+1)
+			if total, carry = bits.Add64(total, size, 0); variable || carry != 0 {
+				return 0, true
+			}
+		}
+		return total, false
+	default:
+		return 0, true
+	}
+}
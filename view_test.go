@@ -0,0 +1,170 @@
+package cuessz
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestView(t *testing.T) {
+	schema := sszSchema(t)
+	data, err := schema.MarshalSSZ("Mixed", mixedValue())
+	if err != nil {
+		t.Fatalf("MarshalSSZ failed: %v", err)
+	}
+	view := schema.View("Mixed", data)
+
+	if n, err := view.Field("flags").Uint64(); err != nil || n != 7 {
+		t.Errorf("flags = %d, %v", n, err)
+	}
+	if n, err := view.Field("checkpoints").Index(0).Field("epoch").Uint64(); err != nil || n != 18446744073709551615 {
+		t.Errorf("checkpoints[0].epoch = %d, %v", n, err)
+	}
+	if b, err := view.Field("checkpoints").Index(0).Field("root").Bytes(); err != nil || !bytes.Equal(b, []byte{1, 2, 3, 4}) {
+		t.Errorf("checkpoints[0].root = %x, %v", b, err)
+	}
+	if b, err := view.Field("extra_data").Bytes(); err != nil || !bytes.Equal(b, []byte{0xca, 0xfe}) {
+		t.Errorf("extra_data = %x, %v", b, err)
+	}
+	if n, err := view.Field("bits").Len(); err != nil || n != 3 {
+		t.Errorf("len(bits) = %d, %v", n, err)
+	}
+	if bit, err := view.Field("justification").Bit(1); err != nil || !bit {
+		t.Errorf("justification[1] = %v, %v", bit, err)
+	}
+	if ok, err := view.Field("ok").Bool(); err != nil || !ok {
+		t.Errorf("ok = %v, %v", ok, err)
+	}
+	if n, err := view.Field("balance").BigInt(); err != nil || n.Cmp(mixedValue()["balance"].(*big.Int)) != 0 {
+		t.Errorf("balance = %v, %v", n, err)
+	}
+	if v, err := view.Decode(); err != nil || !reflect.DeepEqual(v, mixedValue()) {
+		t.Errorf("Decode = %#v, %v", v, err)
+	}
+
+	nested, _ := schema.MarshalSSZ("Nested", []any{[]byte{1}, []byte{}, []byte{2, 3}})
+	for i, want := range [][]byte{{1}, {}, {2, 3}} {
+		if b, err := schema.View("Nested", nested).Index(i).Bytes(); err != nil || !bytes.Equal(b, want) {
+			t.Errorf("Nested[%d] = %x, %v", i, b, err)
+		}
+	}
+
+	// Shorthand byte arrays have uint8 elements without an element field
+	short := sszSchema(t)
+	short.CollapseShorthand()
+	extra := short.View("Mixed", data).Field("extra_data")
+	if n, err := extra.Len(); err != nil || n != 2 {
+		t.Errorf("len(extra_data) = %d, %v", n, err)
+	}
+	if b, err := extra.Index(1).Uint64(); err != nil || b != 0xfe {
+		t.Errorf("extra_data[1] = %d, %v", b, err)
+	}
+
+	choice, _ := schema.MarshalSSZ("Choice", UnionValue{Selector: 0, Value: uint64(5)})
+	if n, err := schema.View("Choice", choice).Option().Uint64(); err != nil || n != 5 {
+		t.Errorf("Choice option = %d, %v", n, err)
+	}
+}
+
+func TestView_Errors(t *testing.T) {
+	schema := sszSchema(t)
+	data, _ := schema.MarshalSSZ("Mixed", mixedValue())
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"missing def", schema.View("Missing", data).Err(), "def 'Missing' not found"},
+		{"missing field", schema.View("Mixed", data).Field("nope").Field("x").Err(), "field 'nope': no such field"},
+		{"index out of range", schema.View("Mixed", data).Field("checkpoints").Index(1).Err(), "index 1 out of range (1 elements)"},
+		{"field of a list", schema.View("Mixed", data).Field("checkpoints").Field("epoch").Err(), "list has no fields"},
+		{"truncated", schema.View("Mixed", data[:20]).Field("checkpoints").Err(), "past the end"},
+		{"bad offset", schema.View("Nested", mustHex(t, "0800000007000000")).Index(0).Err(), "out of order"},
+	}
+	for _, tt := range tests {
+		if tt.err == nil || !strings.Contains(tt.err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got: %v", tt.name, tt.want, tt.err)
+		}
+	}
+	if _, err := schema.View("Mixed", data).Field("flags").Bytes(); err == nil {
+		t.Error("expected error reading a uint8 as bytes")
+	}
+
+	// A field after one whose size wraps uint64 must not be read from byte 0
+	wide := NewSchema().
+		Def("Inner", Vector(Uint64(), 1<<32)).
+		Def("Wide", Container("Wide").Field("x", Vector(Ref("Inner"), 1<<29)).Field("y", Uint8()).Def()).
+		MustBuild()
+	if n, err := wide.View("Wide", []byte{5}).Field("y").Uint64(); err == nil {
+		t.Errorf("expected an error reading past an unsizable field, got %d", n)
+	}
+
+}
+
+func TestView_Malformed(t *testing.T) {
+	schema := sszSchema(t)
+	schema.Defs["B"] = BitVector(64)
+	schema.Defs["Padded"] = BitVector(4)
+	schema.Defs["Bits"] = BitList(8)
+	data, _ := schema.MarshalSSZ("Mixed", mixedValue())
+	badBool := bytes.Clone(data)
+	badBool[46] = 2 // ok follows flags, 2 offsets, justification and balance
+
+	_, bitErr := schema.View("B", []byte{1}).Bit(40)
+	_, padErr := schema.View("Padded", []byte{0x11}).Bit(0)
+	_, delimErr := schema.View("Bits", []byte{1, 0}).Len()
+	_, boolErr := schema.View("Mixed", badBool).Field("ok").Bool()
+	_, selErr := schema.View("Choice", []byte{2, 5}).Selector()
+	_, emptyErr := schema.View("Choice", nil).Selector()
+	_, firstErr := schema.View("Nested", mustHex(t, "0300000001")).Len()
+	_, shortErr := schema.View("Nested", mustHex(t, "0800")).Len()
+	_, uintErr := schema.View("Counts", mustHex(t, "01000000ff")).Index(0).Uint64()
+
+	tests := []struct {
+		name   string
+		err    error
+		reason error
+		at     uint64
+	}{
+		{"short bitvector", bitErr, ErrTruncated, 1},
+		{"padding bits", padErr, ErrInvalidBitfield, 0},
+		{"no delimiter", delimErr, ErrInvalidBitfield, 1},
+		{"boolean", boolErr, ErrInvalidBoolean, 46},
+		{"selector", selErr, ErrInvalidSelector, 0},
+		{"empty union", emptyErr, ErrTruncated, 0},
+		{"first offset", firstErr, ErrOffset, 0},
+		{"short offset", shortErr, ErrTruncated, 0},
+		{"element size", uintErr, ErrLength, 0},
+		{"offsets out of order", schema.View("Nested", mustHex(t, "0800000007000000")).Index(0).Err(), ErrOffset, 0},
+		{"field past the end", schema.View("Mixed", data[:20]).Field("checkpoints").Err(), ErrTruncated, 20},
+		{"field offset", schema.View("Small", mustHex(t, "0201ff00000009")).Field("b").Err(), ErrOffset, 2},
+	}
+	for _, tt := range tests {
+		var de *DecodeError
+		if !errors.Is(tt.err, tt.reason) || !errors.As(tt.err, &de) || de.Offset != tt.at {
+			t.Errorf("%s: expected %v at byte %d, got: %v", tt.name, tt.reason, tt.at, tt.err)
+		}
+	}
+}
+
+func TestView_NoAllocs(t *testing.T) {
+	schema := sszSchema(t)
+	data, _ := schema.MarshalSSZ("Mixed", mixedValue())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		view := schema.View("Mixed", data)
+		if _, err := view.Field("checkpoints").Index(0).Field("epoch").Uint64(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := view.Field("checkpoints").Index(0).Field("root").Bytes(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("view navigation allocated %v times per run", allocs)
+	}
+}